@@ -2,9 +2,15 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
 
 	"github.com/tunapro1234/base-agent/src-go/llm"
+	"github.com/tunapro1234/base-agent/src-go/metrics"
 	"github.com/tunapro1234/base-agent/src-go/task"
 	"github.com/tunapro1234/base-agent/src-go/tools"
 )
@@ -16,7 +22,12 @@ type Agent struct {
 	SystemPrompt string
 	Router       *llm.LLMRouter
 	Tools        *tools.ToolRegistry
-	Tasks        *task.TaskStore
+	Tasks        task.Store
+	Logger       hclog.Logger
+
+	// Metrics records request/task observations across the router, tool
+	// registry, and task store. Defaults to a no-op recorder.
+	Metrics metrics.Recorder
 }
 
 // New creates a new Agent.
@@ -44,25 +55,75 @@ func New(name string, cfg AgentConfig, systemPrompt string) *Agent {
 	if err != nil {
 		panic(err)
 	}
-	var store *task.TaskStore
-	if cfg.EnableTaskStore {
-		store = task.NewTaskStore(false, "")
+	store, err := buildTaskStore(cfg)
+	if err != nil {
+		panic(err)
+	}
+	logLevel := cfg.LogLevel
+	if logLevel == "" {
+		logLevel = defaults.LogLevel
+	}
+	logFormat := cfg.LogFormat
+	if logFormat == "" {
+		logFormat = defaults.LogFormat
+	}
+	level := hclog.LevelFromString(logLevel)
+	if level == hclog.NoLevel {
+		level = hclog.Info
 	}
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:       "agent",
+		Level:      level,
+		JSONFormat: logFormat == "json",
+		Output:     os.Stderr,
+	})
+
+	recorder := metrics.Recorder(metrics.NewNop())
+	if cfg.EnableMetrics {
+		recorder = metrics.NewPromRecorder()
+	}
+	toolRegistry := tools.NewToolRegistry()
+	toolRegistry.Metrics = recorder
+	toolRegistry.Logger = logger.Named("tools")
+	router.Metrics = recorder
+	router.Logger = logger.Named("llm")
+	switch st := store.(type) {
+	case *task.TaskStore:
+		st.Metrics = recorder
+		st.Logger = logger.Named("task")
+	case *task.SQLiteStore:
+		st.Metrics = recorder
+		st.Logger = logger.Named("task")
+	case *task.BoltStore:
+		st.Metrics = recorder
+		st.Logger = logger.Named("task")
+	}
+
 	return &Agent{
 		Name:         name,
 		Config:       cfg,
 		SystemPrompt: systemPrompt,
 		Router:       router,
-		Tools:        tools.NewToolRegistry(),
+		Tools:        toolRegistry,
 		Tasks:        store,
+		Logger:       logger,
+		Metrics:      recorder,
 	}
 }
 
-// AddTool registers a tool.
+// AddTool registers a tool using the legacy args-in/string-out handler
+// signature.
 func (a *Agent) AddTool(name string, handler tools.ToolHandler, schema tools.ToolSchema) error {
 	return a.Tools.Register(name, handler, schema)
 }
 
+// AddToolV2 registers a tool using the context-aware handler signature,
+// giving it access to cancellation, a per-tool timeout, and progress
+// reporting.
+func (a *Agent) AddToolV2(name string, handler tools.ToolHandlerV2, schema tools.ToolSchema) error {
+	return a.Tools.RegisterV2(name, handler, schema)
+}
+
 // Execute runs an instruction.
 func (a *Agent) Execute(ctx context.Context, instruction string) AgentResult {
 	var taskID string
@@ -70,18 +131,60 @@ func (a *Agent) Execute(ctx context.Context, instruction string) AgentResult {
 		t := a.Tasks.Create(instruction)
 		taskID = t.ID
 	}
-
 	messages := []llm.Message{
 		{Role: "system", Content: a.SystemPrompt},
 		{Role: "user", Content: instruction},
 	}
+	log := a.Logger.With("task_id", taskID, "provider", a.Config.Provider, "model", a.Config.Model)
+	log.Info("execute started")
+	return a.runLoop(ctx, log, taskID, messages, 0)
+}
+
+// Resume continues a previously checkpointed task from its last committed
+// iteration, rehydrating the message history the task store saved after
+// every round trip. It returns a failed result if the task store is
+// disabled or the task id is unknown.
+func (a *Agent) Resume(ctx context.Context, taskID string) AgentResult {
+	log := a.Logger.With("task_id", taskID, "provider", a.Config.Provider, "model", a.Config.Model)
+	if a.Tasks == nil {
+		log.Error("resume failed", "error", "task store disabled")
+		return AgentResult{Success: false, Output: "", TaskID: taskID}
+	}
+	t, ok := a.Tasks.Get(taskID)
+	if !ok {
+		log.Error("resume failed", "error", "task not found")
+		return AgentResult{Success: false, Output: "", TaskID: taskID}
+	}
+
+	var messages []llm.Message
+	if len(t.Messages) > 0 {
+		if err := json.Unmarshal(t.Messages, &messages); err != nil {
+			log.Error("resume failed", "error", "corrupt checkpoint: "+err.Error())
+			return AgentResult{Success: false, Output: "", TaskID: taskID}
+		}
+	} else {
+		messages = []llm.Message{
+			{Role: "system", Content: a.SystemPrompt},
+			{Role: "user", Content: t.Instruction},
+		}
+	}
+
+	log.Info("execute resumed", "iteration", t.Iteration)
+	return a.runLoop(ctx, log, taskID, messages, t.Iteration)
+}
+
+// runLoop drives the tool-calling iteration loop shared by Execute and
+// Resume, checkpointing the message history after every iteration so a
+// crashed process can pick back up via Resume.
+func (a *Agent) runLoop(ctx context.Context, log hclog.Logger, taskID string, messages []llm.Message, startIteration int) AgentResult {
+	start := time.Now()
 
 	var toolSchemas []tools.ToolSchema
 	if a.Tools.Count() > 0 {
 		toolSchemas = a.Tools.GetSchemas()
 	}
 
-	for i := 0; i < a.Config.MaxIterations; i++ {
+	for i := startIteration; i < a.Config.MaxIterations; i++ {
 		temp := a.Config.Temperature
 		request := llm.CompletionRequest{
 			Messages:    messages,
@@ -95,28 +198,146 @@ func (a *Agent) Execute(ctx context.Context, instruction string) AgentResult {
 			if a.Tasks != nil {
 				_, _ = a.Tasks.Update(taskID, task.TaskFailed, "", err.Error())
 			}
-			return AgentResult{Success: false, Output: "", TaskID: taskID}
+			log.Error("execute failed", "attempt", i, "error", err.Error(), "duration_ms", time.Since(start).Milliseconds())
+			return AgentResult{Success: false, Output: "", TaskID: taskID, Err: err}
 		}
 		if len(response.ToolCalls) == 0 {
 			if a.Tasks != nil {
 				_, _ = a.Tasks.Update(taskID, task.TaskCompleted, response.Content, "")
 			}
+			log.Info("execute completed", "attempt", i, "duration_ms", time.Since(start).Milliseconds())
 			return AgentResult{Success: true, Output: response.Content, TaskID: taskID}
 		}
 
 		messages = append(messages, llm.Message{Role: "assistant", Content: response.Content})
 		for _, call := range response.ToolCalls {
-			result := a.Tools.Execute(call.Name, call.Args)
+			result := a.Tools.Execute(ctx, call.Name, call.Args, nil)
+			log.Debug("tool executed", "tool", call.Name, "success", result.Success)
 			if !result.Success {
 				messages = append(messages, llm.Message{Role: "user", Content: fmt.Sprintf("Tool %s error: %s", call.Name, result.Error)})
 				continue
 			}
 			messages = append(messages, llm.Message{Role: "user", Content: fmt.Sprintf("Tool %s result: %s", call.Name, result.Output)})
 		}
+
+		if a.Tasks != nil {
+			if encoded, err := json.Marshal(messages); err == nil {
+				_ = a.Tasks.Checkpoint(taskID, i+1, encoded)
+			}
+		}
 	}
 
 	if a.Tasks != nil {
 		_, _ = a.Tasks.Update(taskID, task.TaskFailed, "", "max iterations reached")
 	}
+	log.Warn("execute exhausted max iterations", "duration_ms", time.Since(start).Milliseconds())
 	return AgentResult{Success: false, Output: "", TaskID: taskID}
 }
+
+// ExecuteStream runs an instruction like Execute, but emits incremental
+// StreamEvents through emit as they happen. If the router's provider has
+// no streaming support, it falls back to a single buffered round-trip per
+// iteration and emits the final content as one token event.
+func (a *Agent) ExecuteStream(ctx context.Context, instruction string, emit func(StreamEvent)) AgentResult {
+	var taskID string
+	if a.Tasks != nil {
+		t := a.Tasks.Create(instruction)
+		taskID = t.ID
+	}
+
+	messages := []llm.Message{
+		{Role: "system", Content: a.SystemPrompt},
+		{Role: "user", Content: instruction},
+	}
+
+	var toolSchemas []tools.ToolSchema
+	if a.Tools.Count() > 0 {
+		toolSchemas = a.Tools.GetSchemas()
+	}
+
+	for i := 0; i < a.Config.MaxIterations; i++ {
+		temp := a.Config.Temperature
+		request := llm.CompletionRequest{
+			Messages:    messages,
+			Tools:       toolSchemas,
+			Temperature: &temp,
+			Model:       a.Config.Model,
+			Provider:    a.Config.Provider,
+		}
+
+		response, err := a.completeStream(ctx, request, emit)
+		if err != nil {
+			if a.Tasks != nil {
+				_, _ = a.Tasks.Update(taskID, task.TaskFailed, "", err.Error())
+			}
+			result := AgentResult{Success: false, Output: "", TaskID: taskID, Err: err}
+			emit(StreamEvent{Type: StreamDone, Error: err.Error(), Result: result})
+			return result
+		}
+		if len(response.ToolCalls) == 0 {
+			if a.Tasks != nil {
+				_, _ = a.Tasks.Update(taskID, task.TaskCompleted, response.Content, "")
+			}
+			result := AgentResult{Success: true, Output: response.Content, TaskID: taskID}
+			emit(StreamEvent{Type: StreamDone, Result: result})
+			return result
+		}
+
+		messages = append(messages, llm.Message{Role: "assistant", Content: response.Content})
+		for _, call := range response.ToolCalls {
+			emit(StreamEvent{Type: StreamToolCall, Tool: call.Name})
+			result := a.Tools.Execute(ctx, call.Name, call.Args, func(p tools.ToolProgress) {
+				emit(StreamEvent{Type: StreamToolProgress, Tool: call.Name, Output: p.Message})
+			})
+			emit(StreamEvent{Type: StreamToolResult, Tool: call.Name, Output: result.Output, Error: result.Error})
+			if !result.Success {
+				messages = append(messages, llm.Message{Role: "user", Content: fmt.Sprintf("Tool %s error: %s", call.Name, result.Error)})
+				continue
+			}
+			messages = append(messages, llm.Message{Role: "user", Content: fmt.Sprintf("Tool %s result: %s", call.Name, result.Output)})
+		}
+
+		if a.Tasks != nil {
+			if encoded, err := json.Marshal(messages); err == nil {
+				_ = a.Tasks.Checkpoint(taskID, i+1, encoded)
+			}
+		}
+	}
+
+	if a.Tasks != nil {
+		_, _ = a.Tasks.Update(taskID, task.TaskFailed, "", "max iterations reached")
+	}
+	result := AgentResult{Success: false, Output: "", TaskID: taskID}
+	emit(StreamEvent{Type: StreamDone, Error: "max iterations reached", Result: result})
+	return result
+}
+
+// completeStream emits token events as they arrive from the router's
+// streaming API, or falls back to a single blocking Complete call and
+// emits its content as one token event when streaming isn't supported.
+func (a *Agent) completeStream(ctx context.Context, request llm.CompletionRequest, emit func(StreamEvent)) (llm.LLMResponse, error) {
+	chunks, err := a.Router.Stream(ctx, request)
+	if err != nil {
+		response, err := a.Router.Complete(ctx, request)
+		if err != nil {
+			return llm.LLMResponse{}, err
+		}
+		if response.Content != "" {
+			emit(StreamEvent{Type: StreamToken, Token: response.Content})
+		}
+		return response, nil
+	}
+
+	var final llm.LLMResponse
+	for chunk := range chunks {
+		if chunk.Content != "" {
+			emit(StreamEvent{Type: StreamToken, Token: chunk.Content})
+		}
+		final.Content += chunk.Content
+		final.ToolCalls = append(final.ToolCalls, chunk.ToolCalls...)
+		if chunk.Raw != nil {
+			final.Raw = chunk.Raw
+		}
+	}
+	return final, nil
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	agent "github.com/tunapro1234/base-agent/src-go"
+	"github.com/tunapro1234/base-agent/src-go/task"
+)
+
+func init() {
+	encoding.RegisterCodec(grpcJSONCodec{})
+}
+
+// grpcJSONCodec mirrors debug_cli's client-side jsonCodec: it lets the
+// BaseAgent gRPC service exchange the same JSON-tagged request/response
+// types the HTTP and WS transports already use, instead of requiring a
+// protoc step in a tree that has none. Registering it under the "json"
+// content-subtype is what lets grpc-go pick it for calls made with
+// grpc.ForceCodec(jsonCodec{}) on the client.
+type grpcJSONCodec struct{}
+
+func (grpcJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (grpcJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (grpcJSONCodec) Name() string {
+	return "json"
+}
+
+// baseAgentServiceDesc registers AgentServer's Execute/ListTasks/
+// ExecuteStream RPCs by hand, the same JSON-over-gRPC approach
+// grpc_client.go uses on the debug CLI side, since this tree has no
+// protoc-generated stubs for proto/baseagent.proto. HandlerType is left
+// nil (via the any-pointer trick) so RegisterService skips the
+// interface-assertion check generated code would normally get for free.
+var baseAgentServiceDesc = grpc.ServiceDesc{
+	ServiceName: "baseagent.BaseAgent",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Execute", Handler: grpcExecuteHandler},
+		{MethodName: "ListTasks", Handler: grpcListTasksHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "ExecuteStream", Handler: grpcExecuteStreamHandler, ServerStreams: true},
+	},
+	Metadata: "proto/baseagent.proto",
+}
+
+// newGRPCServer builds the grpc.Server backing AgentServer's share of the
+// multiplexed HTTP/gRPC listener (see rootHandler), with s registered as
+// the baseagent.BaseAgent service implementation.
+func newGRPCServer(s *AgentServer) *grpc.Server {
+	srv := grpc.NewServer(grpc.ForceServerCodec(grpcJSONCodec{}))
+	srv.RegisterService(&baseAgentServiceDesc, s)
+	return srv
+}
+
+func grpcExecuteHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	s := srv.(*AgentServer)
+	req := new(ExecuteRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.grpcExecute(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: s, FullMethod: "/baseagent.BaseAgent/Execute"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.grpcExecute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func (s *AgentServer) grpcExecute(ctx context.Context, req *ExecuteRequest) (any, error) {
+	if req.Instruction == "" {
+		return nil, status.Error(codes.InvalidArgument, "instruction required")
+	}
+	result := s.ExecuteWithOverrides(ctx, *req)
+	resp := &ExecuteResponse{
+		Success: result.Success,
+		Output:  result.Output,
+		TaskID:  result.TaskID,
+		Trace:   result.Trace,
+	}
+	if result.Err != nil {
+		resp.Error = result.Err.Error()
+	}
+	return resp, nil
+}
+
+// grpcListTasksRequest/grpcListTasksResponse are the JSON shapes of
+// proto/baseagent.proto's ListTasksRequest/ListTasksResponse messages.
+type grpcListTasksRequest struct {
+	Limit int `json:"limit"`
+}
+
+type grpcListTasksResponse struct {
+	Tasks []task.Task `json:"tasks"`
+}
+
+func grpcListTasksHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	s := srv.(*AgentServer)
+	req := new(grpcListTasksRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.grpcListTasks(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: s, FullMethod: "/baseagent.BaseAgent/ListTasks"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.grpcListTasks(ctx, req.(*grpcListTasksRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func (s *AgentServer) grpcListTasks(ctx context.Context, req *grpcListTasksRequest) (any, error) {
+	if s.Agent.Tasks == nil {
+		return nil, status.Error(codes.Unavailable, "task store disabled")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	tasks, _, err := s.Agent.Tasks.List(task.Filter{}, limit, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &grpcListTasksResponse{Tasks: tasks}, nil
+}
+
+// grpcStreamEvent is the JSON shape of proto/baseagent.proto's
+// StreamEvent message.
+type grpcStreamEvent struct {
+	Type   string `json:"type,omitempty"`
+	Delta  string `json:"delta,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func grpcExecuteStreamHandler(srv any, stream grpc.ServerStream) error {
+	s := srv.(*AgentServer)
+	var req ExecuteRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	if req.Instruction == "" {
+		return status.Error(codes.InvalidArgument, "instruction required")
+	}
+
+	var sendErr error
+	s.ExecuteStreamWithOverrides(stream.Context(), req, func(ev agent.StreamEvent) {
+		if sendErr != nil {
+			return
+		}
+		switch ev.Type {
+		case agent.StreamToken:
+			sendErr = stream.SendMsg(&grpcStreamEvent{Type: "token", Delta: ev.Token})
+		case agent.StreamToolCall:
+			sendErr = stream.SendMsg(&grpcStreamEvent{Type: "tool_call", Name: ev.Tool})
+		case agent.StreamToolProgress:
+			sendErr = stream.SendMsg(&grpcStreamEvent{Type: "tool_progress", Name: ev.Tool, Output: ev.Output})
+		case agent.StreamToolResult:
+			sendErr = stream.SendMsg(&grpcStreamEvent{Type: "tool_result", Name: ev.Tool, Output: ev.Output, Error: ev.Error})
+		case agent.StreamDone:
+			sendErr = stream.SendMsg(&grpcStreamEvent{Type: "done", Output: ev.Result.Output, Error: ev.Error})
+		}
+	})
+	return sendErr
+}
+
+// isGRPCRequest reports whether r is a gRPC call rather than a plain HTTP
+// request, so rootHandler can route both off the same listener: gRPC
+// always negotiates HTTP/2 and tags its content type "application/grpc"
+// (optionally suffixed with "+<codec>", e.g. "application/grpc+json").
+func isGRPCRequest(contentType string, protoMajor int) bool {
+	return protoMajor == 2 && strings.HasPrefix(contentType, "application/grpc")
+}
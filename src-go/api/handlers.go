@@ -1,12 +1,27 @@
 package api
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	agent "github.com/tunapro1234/base-agent/src-go"
+	"github.com/tunapro1234/base-agent/src-go/llm"
+	"github.com/tunapro1234/base-agent/src-go/task"
 )
 
+// newRequestID returns a correlation id shared by every log line emitted
+// while handling one request, mirroring the task ID scheme in task.Store.
+func newRequestID() string {
+	return fmt.Sprintf("req_%d", time.Now().UnixNano())
+}
+
 // ExecuteRequest is the POST /execute payload.
 type ExecuteRequest struct {
 	Instruction  string   `json:"instruction"`
@@ -19,11 +34,11 @@ type ExecuteRequest struct {
 
 // ExecuteResponse is the POST /execute response.
 type ExecuteResponse struct {
-	Success bool            `json:"success"`
-	Output  string          `json:"output"`
-	TaskID  string          `json:"task_id,omitempty"`
-	Trace   map[string]any  `json:"trace,omitempty"`
-	Error   string          `json:"error,omitempty"`
+	Success bool           `json:"success"`
+	Output  string         `json:"output"`
+	TaskID  string         `json:"task_id,omitempty"`
+	Trace   map[string]any `json:"trace,omitempty"`
+	Error   string         `json:"error,omitempty"`
 }
 
 func (s *AgentServer) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -41,8 +56,18 @@ func (s *AgentServer) handleTasks(w http.ResponseWriter, r *http.Request) {
 			limit = parsed
 		}
 	}
-	tasks := s.Agent.Tasks.List(limit)
-	writeJSON(w, http.StatusOK, map[string]any{"tasks": tasks})
+	var filter task.Filter
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = task.TaskStatus(status)
+	}
+	cursor := r.URL.Query().Get("cursor")
+
+	tasks, next, err := s.Agent.Tasks.List(filter, limit, cursor)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tasks": tasks, "next_cursor": next})
 }
 
 func (s *AgentServer) handleExecute(w http.ResponseWriter, r *http.Request) {
@@ -59,16 +84,218 @@ func (s *AgentServer) handleExecute(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "instruction required"})
 		return
 	}
-	result := s.ExecuteWithOverrides(context.Background(), req)
+
+	requestID := newRequestID()
+	log := s.Logger.With("request_id", requestID, "path", r.URL.Path)
+	start := time.Now()
+	log.Info("request received")
+
+	result := s.ExecuteWithOverrides(r.Context(), req)
+	log.Info("request completed", "task_id", result.TaskID, "success", result.Success, "duration_ms", time.Since(start).Milliseconds())
+
+	var unhealthy llm.ErrAllKeysUnhealthy
+	if errors.As(result.Err, &unhealthy) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": unhealthy.Error(), "task_id": result.TaskID})
+		return
+	}
+	var poolUnavailable llm.PoolUnavailable
+	if errors.As(result.Err, &poolUnavailable) {
+		if poolUnavailable.Reason == llm.AllKeysCoolingDown {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(poolUnavailable.NextReadyAt).Seconds())))
+		}
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": poolUnavailable.Error(), "task_id": result.TaskID})
+		return
+	}
+
 	resp := ExecuteResponse{
 		Success: result.Success,
 		Output:  result.Output,
 		TaskID:  result.TaskID,
 		Trace:   result.Trace,
 	}
+	if result.Err != nil {
+		resp.Error = result.Err.Error()
+	}
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// handleExecuteStream serves POST /execute/stream, emitting SSE `data:`
+// frames as the agent loop produces tokens, tool calls, and tool results.
+// Clients that don't understand SSE should use POST /execute instead.
+func (s *AgentServer) handleExecuteStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+		return
+	}
+	var req ExecuteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid json"})
+		return
+	}
+	if req.Instruction == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "instruction required"})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "streaming unsupported"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeSSE := func(event string, payload any) {
+		data, _ := json.Marshal(payload)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	requestID := newRequestID()
+	log := s.Logger.With("request_id", requestID, "path", r.URL.Path)
+	start := time.Now()
+	log.Info("stream request received")
+
+	s.ExecuteStreamWithOverrides(r.Context(), req, func(ev agent.StreamEvent) {
+		if ev.Type == agent.StreamDone {
+			log.Info("stream request completed", "task_id", ev.Result.TaskID, "success", ev.Result.Success, "duration_ms", time.Since(start).Milliseconds())
+		}
+		switch ev.Type {
+		case agent.StreamToken:
+			writeSSE("token", map[string]any{"delta": ev.Token})
+		case agent.StreamToolCall:
+			writeSSE("tool_call", map[string]any{"name": ev.Tool})
+		case agent.StreamToolProgress:
+			writeSSE("tool_progress", map[string]any{"name": ev.Tool, "message": ev.Output})
+		case agent.StreamToolResult:
+			writeSSE("tool_result", map[string]any{"name": ev.Tool, "output": ev.Output, "error": ev.Error})
+		case agent.StreamTrace:
+			writeSSE("trace", map[string]any{"trace": ev.Result.Trace})
+		case agent.StreamDone:
+			writeSSE("done", ExecuteResponse{
+				Success: ev.Result.Success,
+				Output:  ev.Result.Output,
+				TaskID:  ev.Result.TaskID,
+				Trace:   ev.Result.Trace,
+				Error:   ev.Error,
+			})
+		}
+	})
+}
+
+// handleTasksWatch serves GET /tasks/watch, pushing every task lifecycle
+// event (created, updated, checkpointed) as an SSE frame until the client
+// disconnects.
+func (s *AgentServer) handleTasksWatch(w http.ResponseWriter, r *http.Request) {
+	if s.Agent.Tasks == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "task store disabled"})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "streaming unsupported"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	events := s.Agent.Tasks.Watch(r.Context())
+	for ev := range events {
+		data, _ := json.Marshal(map[string]any{"type": ev.Type, "task": ev.Task})
+		fmt.Fprintf(w, "event: task\ndata: %s\n\n", data)
+		flusher.Flush()
+	}
+}
+
+// handleTaskResume serves POST /tasks/{id}/resume, continuing a pending or
+// running task from its last checkpointed iteration.
+func (s *AgentServer) handleTaskResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/resume") {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+		return
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/resume")
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "task id required"})
+		return
+	}
+	if s.Agent.Tasks == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "task store disabled"})
+		return
+	}
+
+	requestID := newRequestID()
+	log := s.Logger.With("request_id", requestID, "path", r.URL.Path, "task_id", id)
+	start := time.Now()
+	log.Info("resume request received")
+
+	result := s.Agent.Resume(r.Context(), id)
+	log.Info("resume request completed", "success", result.Success, "duration_ms", time.Since(start).Milliseconds())
+	writeJSON(w, http.StatusOK, ExecuteResponse{
+		Success: result.Success,
+		Output:  result.Output,
+		TaskID:  result.TaskID,
+		Trace:   result.Trace,
+	})
+}
+
+// handleKeys serves GET /keys, reporting the rotation health of every API
+// key configured on each registered provider.
+func (s *AgentServer) handleKeys(w http.ResponseWriter, r *http.Request) {
+	if s.Agent.Router == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "router disabled"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": s.Agent.Router.KeySnapshot()})
+}
+
+// handleProviders serves GET /v1/providers, reporting per-key rotation
+// and circuit breaker health for every registered LLM provider.
+func (s *AgentServer) handleProviders(w http.ResponseWriter, r *http.Request) {
+	if s.Agent.Router == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "router disabled"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"providers": s.Agent.Router.Providers()})
+}
+
+// logLevelRequest is the PUT /v1/log-level payload.
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// handleLogLevel serves PUT /v1/log-level, atomically swapping the shared
+// logger's minimum level without a restart. The new level applies
+// immediately to every Logger derived from it via With/Named.
+func (s *AgentServer) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+		return
+	}
+	var req logLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid json"})
+		return
+	}
+	if req.Level == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "level required"})
+		return
+	}
+	level := hclog.LevelFromString(req.Level)
+	if level == hclog.NoLevel {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "unrecognized level"})
+		return
+	}
+	s.Logger.SetLevel(level)
+	writeJSON(w, http.StatusOK, map[string]any{"level": level.String()})
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	data, _ := json.Marshal(payload)
 	w.Header().Set("Content-Type", "application/json")
@@ -4,17 +4,32 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/tunapro1234/base-agent/src-go/agent"
+	hclog "github.com/hashicorp/go-hclog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+
+	agent "github.com/tunapro1234/base-agent/src-go"
+	"github.com/tunapro1234/base-agent/src-go/metrics"
 )
 
 // AgentServer serves HTTP endpoints.
 type AgentServer struct {
-	Port  int
-	Agent *agent.Agent
-	mux   *http.ServeMux
-	mu    sync.Mutex
+	Port   int
+	Agent  *agent.Agent
+	Logger hclog.Logger
+	mux    *http.ServeMux
+	mu     sync.Mutex
+
+	cancelMu sync.Mutex
+	cancels  map[string]context.CancelFunc
+
+	grpcServer *grpc.Server
 }
 
 // NewAgentServer creates a server.
@@ -22,14 +37,72 @@ func NewAgentServer(port int, agentInstance *agent.Agent) *AgentServer {
 	if agentInstance == nil {
 		agentInstance = agent.New("api-agent", agent.DefaultAgentConfig(), "")
 	}
-	return &AgentServer{Port: port, Agent: agentInstance, mux: http.NewServeMux()}
+	logger := agentInstance.Logger
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	s := &AgentServer{
+		Port:    port,
+		Agent:   agentInstance,
+		Logger:  logger.Named("api"),
+		mux:     http.NewServeMux(),
+		cancels: map[string]context.CancelFunc{},
+	}
+	s.grpcServer = newGRPCServer(s)
+	return s
+}
+
+// registerCancel tracks cancel for an in-flight task so a later "cancel"
+// frame on /ws can abort it.
+func (s *AgentServer) registerCancel(taskID string, cancel context.CancelFunc) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	s.cancels[taskID] = cancel
+}
+
+// clearCancel forgets a task's cancel func once it's done, successfully or
+// not.
+func (s *AgentServer) clearCancel(taskID string) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	delete(s.cancels, taskID)
+}
+
+// cancelTask aborts the in-flight execution registered under taskID, if
+// any, returning whether one was found.
+func (s *AgentServer) cancelTask(taskID string) bool {
+	s.cancelMu.Lock()
+	cancel, ok := s.cancels[taskID]
+	s.cancelMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
 }
 
-// Start starts the HTTP server.
+// Start starts the HTTP server. The BaseAgent gRPC service (see
+// grpc_server.go) is served off the same port: both transports share one
+// listener, routed per-request by rootHandler, since GRPCClient dials the
+// same base URL/port the HTTP and WS transports use.
 func (s *AgentServer) Start() error {
 	s.routes()
 	addr := fmt.Sprintf(":%d", s.Port)
-	return http.ListenAndServe(addr, s.mux)
+	h2s := &http2.Server{}
+	return http.ListenAndServe(addr, h2c.NewHandler(s.rootHandler(), h2s))
+}
+
+// rootHandler dispatches each request to the gRPC server or the plain
+// HTTP mux, the two distinguishable by gRPC's fixed HTTP/2 +
+// "application/grpc" content type. h2c lets both share one cleartext
+// listener without requiring TLS-based ALPN negotiation.
+func (s *AgentServer) rootHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPCRequest(r.Header.Get("Content-Type"), r.ProtoMajor) {
+			s.grpcServer.ServeHTTP(w, r)
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
 }
 
 // ExecuteWithOverrides runs the agent with request overrides.
@@ -63,8 +136,89 @@ func (s *AgentServer) ExecuteWithOverrides(ctx context.Context, req ExecuteReque
 	return result
 }
 
+// ExecuteStreamWithOverrides runs the agent in streaming mode with request
+// overrides, emitting each StreamEvent through emit as it happens.
+func (s *AgentServer) ExecuteStreamWithOverrides(ctx context.Context, req ExecuteRequest, emit func(agent.StreamEvent)) agent.AgentResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	origCfg := s.Agent.Config
+	origPrompt := s.Agent.SystemPrompt
+
+	cfg := origCfg
+	if req.Provider != "" {
+		cfg.Provider = req.Provider
+	}
+	if req.Model != "" {
+		cfg.Model = req.Model
+	}
+	if req.Temperature != nil {
+		cfg.Temperature = *req.Temperature
+	}
+	s.Agent.Config = cfg
+	if req.SystemPrompt != "" {
+		s.Agent.SystemPrompt = req.SystemPrompt
+	}
+
+	result := s.Agent.ExecuteStream(ctx, req.Instruction, emit)
+
+	s.Agent.Config = origCfg
+	s.Agent.SystemPrompt = origPrompt
+
+	return result
+}
+
 func (s *AgentServer) routes() {
-	s.mux.HandleFunc("/health", s.handleHealth)
-	s.mux.HandleFunc("/tasks", s.handleTasks)
-	s.mux.HandleFunc("/execute", s.handleExecute)
+	s.mux.HandleFunc("/health", s.withMetrics(s.handleHealth))
+	s.mux.HandleFunc("/tasks", s.withMetrics(s.handleTasks))
+	s.mux.HandleFunc("/execute", s.withMetrics(s.handleExecute))
+	s.mux.HandleFunc("/execute/stream", s.withMetrics(s.handleExecuteStream))
+	s.mux.HandleFunc("/keys", s.withMetrics(s.handleKeys))
+	s.mux.HandleFunc("/tasks/watch", s.handleTasksWatch)
+	s.mux.HandleFunc("/tasks/", s.withMetrics(s.handleTaskResume))
+	s.mux.HandleFunc("/v1/log-level", s.withMetrics(s.handleLogLevel))
+	s.mux.HandleFunc("/v1/providers", s.withMetrics(s.handleProviders))
+	s.mux.HandleFunc("/ws", s.handleWS)
+
+	if prom, ok := s.Agent.Metrics.(*metrics.PromRecorder); ok {
+		path := s.Agent.Config.MetricsPath
+		if path == "" {
+			path = "/metrics"
+		}
+		s.mux.Handle(path, prom.Handler(s.Agent.Config.MetricsToken))
+	}
+}
+
+// statusRecorder captures the status code a handler writes, so withMetrics
+// can report it without every handler doing so itself.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// withMetrics wraps a handler to record its latency and status in
+// Agent.Metrics under the "http_requests_total"/"http_request_duration"
+// series.
+func (s *AgentServer) withMetrics(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next(rec, r)
+		s.Agent.Metrics.ObserveHTTPRequest(routeTemplate(r.URL.Path), r.Method, strconv.Itoa(rec.status), time.Since(start))
+	}
+}
+
+// routeTemplate collapses a request path down to the route template it
+// matched, so a path carrying a variable id (like /tasks/{id}/resume)
+// doesn't become its own Prometheus label series per distinct task.
+func routeTemplate(path string) string {
+	if strings.HasPrefix(path, "/tasks/") && strings.HasSuffix(path, "/resume") {
+		return "/tasks/{id}/resume"
+	}
+	return path
 }
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	hclog "github.com/hashicorp/go-hclog"
+
+	agent "github.com/tunapro1234/base-agent/src-go"
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// wsMessage is one frame of the /ws protocol. Clients send "execute" and
+// "cancel" frames; the server replies with "token", "tool_call",
+// "tool_result", and "done" frames, all correlated by task_id.
+type wsMessage struct {
+	Type         string   `json:"type"`
+	TaskID       string   `json:"task_id,omitempty"`
+	Instruction  string   `json:"instruction,omitempty"`
+	SystemPrompt string   `json:"system_prompt,omitempty"`
+	Provider     string   `json:"provider,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+	Delta        string   `json:"delta,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Output       string   `json:"output,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Success      bool     `json:"success,omitempty"`
+}
+
+// handleWS upgrades to a single long-lived WebSocket connection so the
+// debug REPL can run many turns without a new HTTP round trip per turn,
+// and so a "cancel" frame can abort an in-flight turn via the
+// context.CancelFunc registered for its task_id.
+func (s *AgentServer) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	log := s.Logger.With("path", r.URL.Path)
+
+	// writeMu serializes conn.WriteJSON across every in-flight execute, since
+	// gorilla/websocket allows only one writer at a time; wg lets the read
+	// loop's deferred Close wait for them to finish instead of yanking the
+	// connection out from under a goroutine still writing.
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case "execute":
+			wg.Add(1)
+			go func(msg wsMessage) {
+				defer wg.Done()
+				s.handleWSExecute(conn, &writeMu, log, msg)
+			}(msg)
+		case "cancel":
+			s.cancelTask(msg.TaskID)
+		}
+	}
+}
+
+// handleWSExecute runs one turn. It's spawned in its own goroutine by
+// handleWS so the read loop stays free to receive a "cancel" frame (or a
+// second "execute") while this turn is still in flight.
+func (s *AgentServer) handleWSExecute(conn *websocket.Conn, writeMu *sync.Mutex, log hclog.Logger, msg wsMessage) {
+	req := ExecuteRequest{
+		Instruction:  msg.Instruction,
+		SystemPrompt: msg.SystemPrompt,
+		Provider:     msg.Provider,
+		Model:        msg.Model,
+		Temperature:  msg.Temperature,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	taskID := newRequestID()
+	s.registerCancel(taskID, cancel)
+	defer s.clearCancel(taskID)
+
+	write := func(m wsMessage) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.WriteJSON(m)
+	}
+
+	s.ExecuteStreamWithOverrides(ctx, req, func(ev agent.StreamEvent) {
+		switch ev.Type {
+		case agent.StreamToken:
+			write(wsMessage{Type: "token", TaskID: taskID, Delta: ev.Token})
+		case agent.StreamToolCall:
+			write(wsMessage{Type: "tool_call", TaskID: taskID, Name: ev.Tool})
+		case agent.StreamToolResult:
+			write(wsMessage{Type: "tool_result", TaskID: taskID, Name: ev.Tool, Output: ev.Output, Error: ev.Error})
+		case agent.StreamDone:
+			log.Info("ws execute completed", "task_id", taskID, "success", ev.Result.Success)
+			write(wsMessage{Type: "done", TaskID: taskID, Success: ev.Result.Success, Output: ev.Result.Output, Error: ev.Error})
+		}
+	})
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
-	"log"
+	"flag"
 	"os"
 	"strconv"
+	"time"
 
 	agent "github.com/tunapro1234/base-agent/src-go"
 	"github.com/tunapro1234/base-agent/src-go/api"
@@ -17,10 +18,62 @@ func main() {
 		}
 	}
 
-	inst := agent.New("go-agent", agent.DefaultAgentConfig(), "")
+	cfg := agent.DefaultAgentConfig()
+	flag.StringVar(&cfg.LogLevel, "log-level", envOr("LOG_LEVEL", cfg.LogLevel), "Log level: trace, debug, info, warn, error, off")
+	flag.StringVar(&cfg.LogFormat, "log-format", envOr("LOG_FORMAT", cfg.LogFormat), "Log format: text or json")
+	flag.StringVar(&cfg.RotationStrategy, "rotation-strategy", envOr("ROTATION_STRATEGY", cfg.RotationStrategy), "API key rotation strategy: round_robin, random, least_recently_used, weighted_by_quota, health_aware")
+	flag.StringVar(&cfg.TaskStore, "task-store", envOr("TASK_STORE", cfg.TaskStore), "Task store backend: memory, sqlite, or bolt")
+	flag.StringVar(&cfg.TaskDB, "task-db", envOr("TASK_DB", cfg.TaskDB), "File path/DSN for the sqlite or bolt task store backend")
+	flag.BoolVar(&cfg.EnableMetrics, "enable-metrics", envBoolOr("ENABLE_METRICS", cfg.EnableMetrics), "Serve Prometheus metrics")
+	flag.StringVar(&cfg.MetricsPath, "metrics-path", envOr("METRICS_PATH", cfg.MetricsPath), "HTTP path the /metrics endpoint is served on")
+	flag.StringVar(&cfg.MetricsToken, "metrics-token", envOr("METRICS_TOKEN", cfg.MetricsToken), "Bearer token required to read the metrics endpoint, if set")
+	flag.DurationVar(&cfg.StreamIdleTimeout, "stream-idle-timeout", envDurationOr("STREAM_IDLE_TIMEOUT", cfg.StreamIdleTimeout), "Max idle time between streaming completion chunks before giving up")
+	flag.IntVar(&cfg.RetryMaxAttempts, "retry-max-attempts", envIntOr("RETRY_MAX_ATTEMPTS", cfg.RetryMaxAttempts), "Max keys to try for one completion request before giving up")
+	flag.DurationVar(&cfg.RetryBaseDelay, "retry-base-delay", envDurationOr("RETRY_BASE_DELAY", cfg.RetryBaseDelay), "Base delay for exponential backoff between retries")
+	flag.DurationVar(&cfg.RetryMaxDelay, "retry-max-delay", envDurationOr("RETRY_MAX_DELAY", cfg.RetryMaxDelay), "Max delay for exponential backoff between retries")
+	flag.IntVar(&cfg.BreakerThreshold, "breaker-threshold", envIntOr("BREAKER_THRESHOLD", cfg.BreakerThreshold), "Consecutive failures before an API key's circuit breaker trips open")
+	flag.DurationVar(&cfg.BreakerCooldown, "breaker-cooldown", envDurationOr("BREAKER_COOLDOWN", cfg.BreakerCooldown), "Cooldown before a tripped API key gets a half-open probe")
+	flag.Parse()
+
+	inst := agent.New("go-agent", cfg, "")
 	server := api.NewAgentServer(port, inst)
-	log.Printf("base-agent listening on :%d", port)
+	server.Logger.Info("base-agent listening", "port", port, "log_level", cfg.LogLevel, "log_format", cfg.LogFormat)
 	if err := server.Start(); err != nil {
-		log.Fatalf("server error: %v", err)
+		server.Logger.Error("server error", "error", err.Error())
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func envBoolOr(key string, fallback bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func envDurationOr(key string, fallback time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if parsed, err := time.ParseDuration(val); err == nil {
+			return parsed
+		}
 	}
+	return fallback
 }
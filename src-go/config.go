@@ -1,5 +1,7 @@
 package agent
 
+import "time"
+
 // AgentConfig controls agent execution.
 type AgentConfig struct {
 	Provider        string
@@ -8,7 +10,51 @@ type AgentConfig struct {
 	MaxIterations   int
 	Temperature     float64
 	EnableTaskStore bool
-	CodexAuthFile   string
+	// TaskStore selects the task store backend: "memory" (default),
+	// "sqlite", or "bolt". The persistent backends read/write TaskDB.
+	TaskStore string
+	// TaskDB is the file path/DSN used by the "sqlite" and "bolt"
+	// TaskStore backends; unused by "memory".
+	TaskDB        string
+	CodexAuthFile string
+	LogLevel      string
+	LogFormat     string
+
+	// RotationStrategy selects how provider adapters pick among multiple
+	// configured API keys: "round_robin" (default), "random",
+	// "least_recently_used", "weighted_by_quota", or "health_aware".
+	RotationStrategy string
+
+	// EnableMetrics turns on Prometheus instrumentation for the router,
+	// tool registry, and task store, and serves it on MetricsPath.
+	EnableMetrics bool
+	// MetricsPath is the HTTP path the /metrics endpoint is served on.
+	// Defaults to "/metrics".
+	MetricsPath string
+	// MetricsToken, if set, requires "Authorization: Bearer <token>" on
+	// requests to MetricsPath.
+	MetricsToken string
+
+	// StreamIdleTimeout bounds how long a streaming completion waits
+	// between provider chunks before giving up on a stalled connection.
+	// Zero uses the provider adapter's own default.
+	StreamIdleTimeout time.Duration
+
+	// RetryMaxAttempts caps how many keys a provider adapter tries for one
+	// Complete call before giving up. Zero uses llm.DefaultRetryPolicy.
+	RetryMaxAttempts int
+	// RetryBaseDelay and RetryMaxDelay bound the capped exponential
+	// backoff between retries. Zero uses llm.DefaultRetryPolicy.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerThreshold is the number of consecutive failures an API key
+	// tolerates before its circuit breaker trips open. Zero uses the
+	// provider adapter's own default.
+	BreakerThreshold int
+	// BreakerCooldown is how long a tripped key is skipped before a
+	// half-open probe is allowed. Zero uses the provider adapter's own
+	// default.
+	BreakerCooldown time.Duration
 }
 
 // DefaultAgentConfig returns baseline config values.
@@ -19,5 +65,9 @@ func DefaultAgentConfig() AgentConfig {
 		MaxIterations:   10,
 		Temperature:     0.3,
 		EnableTaskStore: true,
+		TaskStore:       "memory",
+		LogLevel:        "info",
+		LogFormat:       "text",
+		MetricsPath:     "/metrics",
 	}
 }
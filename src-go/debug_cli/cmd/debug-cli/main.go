@@ -11,6 +11,12 @@ import (
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/repl"
 )
 
+const (
+	defaultRetryAttempts = 3
+	defaultRateLimit     = 5.0 // requests/sec per host
+	defaultRateBurst     = 10
+)
+
 func main() {
 	cfg, err := cli.ParseConfig(os.Args[1:])
 	if err != nil {
@@ -21,7 +27,37 @@ func main() {
 		os.Exit(1)
 	}
 
-	httpClient := client.NewHTTPClient(cfg.BaseURL, cfg.Token)
-	console := repl.New(cfg, httpClient)
+	console := repl.New(cfg, nil)
+	console.ClientOpts = []client.Option{client.WithMiddleware(
+		client.RequestIDMiddleware(),
+		client.RetryMiddleware(defaultRetryAttempts),
+		client.RateLimitMiddleware(defaultRateLimit, defaultRateBurst),
+		client.LoggingMiddleware(os.Stderr, func() bool { return console.Config.Debug }),
+	)}
+	httpClient := client.NewHTTPClient(cfg.BaseURL, cfg.Token, console.ClientOpts...)
+	console.Client = httpClient
+	console.Backend = httpClient
+
+	switch cfg.Transport {
+	case "ws":
+		ws, err := client.DialWSClient(cfg.BaseURL, cfg.Token)
+		switch {
+		case err == nil:
+			console.WS = ws
+		case errors.Is(err, client.ErrWSUnsupported):
+			fmt.Fprintln(os.Stderr, "server does not support ws transport, falling back to http")
+		default:
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "grpc":
+		grpcClient, err := client.DialGRPCClient(cfg.BaseURL, cfg.Token)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		console.Backend = grpcClient
+	}
+
 	console.Run()
 }
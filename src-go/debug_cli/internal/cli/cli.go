@@ -8,35 +8,78 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/config"
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
 )
 
 const (
-	envBaseURL   = "BASE_AGENT_URL"
-	envToken     = "BASE_AGENT_TOKEN"
-	envProvider  = "BASE_AGENT_PROVIDER"
-	envModel     = "BASE_AGENT_MODEL"
-	envSystem    = "BASE_AGENT_SYSTEM_PROMPT"
-	envTemp      = "BASE_AGENT_TEMPERATURE"
-	envDebug     = "BASE_AGENT_DEBUG"
-	defaultURL   = "http://localhost:8080"
-	defaultModel = "gemini-3-pro-preview"
-	defaultProv  = "gemini"
+	envBaseURL          = "BASE_AGENT_URL"
+	envToken            = "BASE_AGENT_TOKEN"
+	envProvider         = "BASE_AGENT_PROVIDER"
+	envModel            = "BASE_AGENT_MODEL"
+	envSystem           = "BASE_AGENT_SYSTEM_PROMPT"
+	envTemp             = "BASE_AGENT_TEMPERATURE"
+	envDebug            = "BASE_AGENT_DEBUG"
+	envReadTimeout      = "BASE_AGENT_READ_TIMEOUT"
+	envWriteTimeout     = "BASE_AGENT_WRITE_TIMEOUT"
+	envTransport        = "BASE_AGENT_TRANSPORT"
+	envStream           = "BASE_AGENT_STREAM"
+	envConfigPath       = "BASE_AGENT_CONFIG"
+	defaultURL          = "http://localhost:8080"
+	defaultTransport    = "http"
+	defaultModel        = "gemini-3-pro-preview"
+	defaultProv         = "gemini"
+	defaultReadTimeout  = 30 * time.Second
+	defaultWriteTimeout = 90 * time.Second
 )
 
-// ParseConfig parses CLI flags and environment variables into a config.
+// ParseConfig parses CLI flags, environment variables, and an optional YAML
+// config file into a config, applied in that precedence: flags override
+// env, env overrides the config file's active profile, and the file
+// overrides these hardcoded defaults.
 func ParseConfig(args []string) (models.CLIConfig, error) {
 	cfg := models.CLIConfig{
-		BaseURL:      envOr(envBaseURL, defaultURL),
-		Provider:     envOr(envProvider, defaultProv),
-		Model:        envOr(envModel, defaultModel),
-		SystemPrompt: envOr(envSystem, ""),
-		Temperature:  envFloat(envTemp, 0.3),
-		Debug:        envBool(envDebug, false),
-		Token:        envOr(envToken, ""),
+		BaseURL:      defaultURL,
+		Provider:     defaultProv,
+		Model:        defaultModel,
+		Temperature:  0.3,
+		ReadTimeout:  defaultReadTimeout,
+		WriteTimeout: defaultWriteTimeout,
+		Transport:    defaultTransport,
+		Stream:       true,
 	}
 
+	configPath := scanFlagValue(args, "config")
+	if configPath == "" {
+		configPath = envOr(envConfigPath, "")
+	}
+	if configPath != "" {
+		fileCfg, err := config.Load(configPath)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.ConfigPath = configPath
+		cfg.Profile = fileCfg.DefaultProfile
+		if profile, ok := fileCfg.Profiles[cfg.Profile]; ok {
+			profile.ApplyTo(&cfg)
+		}
+		cfg.ACLRole = fileCfg.AccessControl.Role
+	}
+
+	cfg.BaseURL = envOr(envBaseURL, cfg.BaseURL)
+	cfg.Provider = envOr(envProvider, cfg.Provider)
+	cfg.Model = envOr(envModel, cfg.Model)
+	cfg.SystemPrompt = envOr(envSystem, cfg.SystemPrompt)
+	cfg.Temperature = envFloat(envTemp, cfg.Temperature)
+	cfg.Debug = envBool(envDebug, cfg.Debug)
+	cfg.Token = envOr(envToken, cfg.Token)
+	cfg.ReadTimeout = envDuration(envReadTimeout, cfg.ReadTimeout)
+	cfg.WriteTimeout = envDuration(envWriteTimeout, cfg.WriteTimeout)
+	cfg.Transport = envOr(envTransport, cfg.Transport)
+	cfg.Stream = envBool(envStream, cfg.Stream)
+
 	fs := flag.NewFlagSet("debug-cli", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 
@@ -47,6 +90,11 @@ func ParseConfig(args []string) (models.CLIConfig, error) {
 	fs.Float64Var(&cfg.Temperature, "temp", cfg.Temperature, "Sampling temperature")
 	fs.BoolVar(&cfg.Debug, "debug", cfg.Debug, "Enable debug output")
 	fs.StringVar(&cfg.Token, "token", cfg.Token, "Bearer token")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "Deadline for reading a response")
+	fs.DurationVar(&cfg.WriteTimeout, "write-timeout", cfg.WriteTimeout, "Deadline for sending a request")
+	fs.StringVar(&cfg.Transport, "transport", cfg.Transport, "Transport: http, ws, or grpc")
+	fs.BoolVar(&cfg.Stream, "stream", cfg.Stream, "Use the streaming endpoint when available")
+	fs.StringVar(&cfg.ConfigPath, "config", cfg.ConfigPath, "Path to a YAML config file with provider profiles and access control")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -60,6 +108,28 @@ func ParseConfig(args []string) (models.CLIConfig, error) {
 	return cfg, nil
 }
 
+// scanFlagValue looks up the value passed to -name or --name in args
+// without fully parsing the flag set, so the config file can be loaded
+// before the other flags (which may themselves depend on it as a default)
+// are declared.
+func scanFlagValue(args []string, name string) string {
+	prefix1 := "-" + name
+	prefix2 := "--" + name
+	for i, a := range args {
+		switch {
+		case a == prefix1 || a == prefix2:
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		case strings.HasPrefix(a, prefix1+"="):
+			return strings.TrimPrefix(a, prefix1+"=")
+		case strings.HasPrefix(a, prefix2+"="):
+			return strings.TrimPrefix(a, prefix2+"=")
+		}
+	}
+	return ""
+}
+
 func usage(out io.Writer) {
 	fmt.Fprintln(out, "debug-cli flags:")
 	fmt.Fprintln(out, "  -base URL          Base API URL (default http://localhost:8080)")
@@ -69,6 +139,11 @@ func usage(out io.Writer) {
 	fmt.Fprintln(out, "  -temp FLOAT        Sampling temperature")
 	fmt.Fprintln(out, "  -debug             Enable debug output")
 	fmt.Fprintln(out, "  -token TOKEN       Bearer token")
+	fmt.Fprintln(out, "  -read-timeout DUR  Deadline for reading a response (default 30s)")
+	fmt.Fprintln(out, "  -write-timeout DUR Deadline for sending a request (default 90s)")
+	fmt.Fprintln(out, "  -transport NAME    Transport: http, ws, or grpc (default http)")
+	fmt.Fprintln(out, "  -stream            Use the streaming endpoint when available (default true)")
+	fmt.Fprintln(out, "  -config PATH       YAML config file with provider profiles and access control")
 }
 
 func envOr(key, fallback string) string {
@@ -90,6 +165,18 @@ func envBool(key string, fallback bool) bool {
 	return val
 }
 
+func envDuration(key string, fallback time.Duration) time.Duration {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	val, err := time.ParseDuration(raw)
+	if err != nil {
+		return fallback
+	}
+	return val
+}
+
 func envFloat(key string, fallback float64) float64 {
 	raw := strings.TrimSpace(os.Getenv(key))
 	if raw == "" {
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
+)
+
+// Backend is the transport-agnostic surface the REPL drives a turn
+// through. HTTPClient and GRPCClient both satisfy it, so command handlers
+// don't need to know which transport is active.
+type Backend interface {
+	Execute(ctx context.Context, req models.ExecuteRequest) (models.ExecuteResponse, error)
+	ListTasks(ctx context.Context, limit int) ([]models.TaskInfo, error)
+	ExecuteStream(ctx context.Context, req models.ExecuteRequest) (<-chan models.StreamEvent, error)
+}
+
+var _ Backend = (*HTTPClient)(nil)
+var _ Backend = (*GRPCClient)(nil)
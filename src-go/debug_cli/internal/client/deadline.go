@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithDeadline derives a context from ctx that also ends at t, for a
+// one-shot per-call deadline as an alternative to the rolling one set via
+// HTTPClient.SetReadDeadline/SetWriteDeadline. Unlike those, which can be
+// re-armed mid-request via deadlineTimer's cancel channel, this context's
+// deadline is fixed at creation, matching the standard library's
+// context.WithDeadline contract. A zero t returns ctx unchanged alongside a
+// no-op cancel func.
+func WithDeadline(ctx context.Context, t time.Time) (context.Context, context.CancelFunc) {
+	if t.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, t)
+}
+
+// deadlineTimer implements independent, re-armable read and write deadlines
+// for HTTPClient. Each direction is a *time.Timer paired with a cancel
+// channel that is closed when the timer fires; callers select on the
+// channel to notice an expired deadline. Setting a new deadline while one
+// is already pending is safe: the old timer is stopped and, if it had
+// already fired, a fresh channel is swapped in so stale closes can't leak
+// into the next request.
+type deadlineTimer struct {
+	mu sync.Mutex
+
+	readTimer    *time.Timer
+	readCancelCh chan struct{}
+
+	writeTimer    *time.Timer
+	writeCancelCh chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{
+		readCancelCh:  make(chan struct{}),
+		writeCancelCh: make(chan struct{}),
+	}
+}
+
+// SetReadDeadline arms the read-side cancel channel to close at t. A zero
+// t disarms it (the channel stays open indefinitely).
+func (d *deadlineTimer) SetReadDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readTimer, d.readCancelCh = rearm(d.readTimer, d.readCancelCh, t)
+}
+
+// SetWriteDeadline arms the write-side cancel channel to close at t. A
+// zero t disarms it (the channel stays open indefinitely).
+func (d *deadlineTimer) SetWriteDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.writeTimer, d.writeCancelCh = rearm(d.writeTimer, d.writeCancelCh, t)
+}
+
+func (d *deadlineTimer) readDone() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readCancelCh
+}
+
+func (d *deadlineTimer) writeDone() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeCancelCh
+}
+
+// rearm stops the previous timer, swapping in a fresh cancel channel if it
+// had already fired, then starts a new timer for t (or leaves the
+// deadline disarmed if t is zero). Must be called with d.mu held.
+func rearm(timer *time.Timer, ch chan struct{}, t time.Time) (*time.Timer, chan struct{}) {
+	if timer != nil && !timer.Stop() {
+		ch = make(chan struct{})
+	} else {
+		select {
+		case <-ch:
+		default:
+		}
+	}
+
+	if t.IsZero() {
+		return nil, ch
+	}
+
+	timeout := time.Until(t)
+	captured := ch
+	if timeout <= 0 {
+		close(captured)
+		return nil, ch
+	}
+	timer = time.AfterFunc(timeout, func() {
+		close(captured)
+	})
+	return timer, ch
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
+)
+
+// baseAgentService is the fully-qualified gRPC service name from
+// proto/baseagent.proto.
+const baseAgentService = "/baseagent.BaseAgent/"
+
+// GRPCClient talks to the BaseAgent gRPC service described in
+// proto/baseagent.proto. It wires models.ExecuteRequest/ExecuteResponse
+// straight onto the wire through jsonCodec rather than protoc-generated
+// bindings, since this tree has no protoc step; the .proto stays the
+// source of truth for the service contract.
+type GRPCClient struct {
+	conn  *grpc.ClientConn
+	token string
+}
+
+// DialGRPCClient connects to target, inferring TLS the same way
+// DialWSClient infers ws vs wss: an "https" base URL dials over TLS,
+// anything else dials plaintext.
+func DialGRPCClient(baseURL, token string) (*GRPCClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials.TransportCredentials
+	if u.Scheme == "https" {
+		creds = credentials.NewTLS(&tls.Config{})
+	} else {
+		creds = insecure.NewCredentials()
+	}
+
+	conn, err := grpc.NewClient(u.Host,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCClient{conn: conn, token: token}, nil
+}
+
+// Close closes the underlying connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// Execute calls the unary Execute RPC.
+func (c *GRPCClient) Execute(ctx context.Context, req models.ExecuteRequest) (models.ExecuteResponse, error) {
+	var resp models.ExecuteResponse
+	if err := c.conn.Invoke(ctx, baseAgentService+"Execute", req, &resp, c.perRPCAuth()); err != nil {
+		return models.ExecuteResponse{}, err
+	}
+	return resp, nil
+}
+
+// ListTasks calls the unary ListTasks RPC.
+func (c *GRPCClient) ListTasks(ctx context.Context, limit int) ([]models.TaskInfo, error) {
+	req := struct {
+		Limit int `json:"limit"`
+	}{Limit: limit}
+	var resp struct {
+		Tasks []models.TaskInfo `json:"tasks"`
+	}
+	if err := c.conn.Invoke(ctx, baseAgentService+"ListTasks", req, &resp, c.perRPCAuth()); err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// ExecuteStream calls the server-streaming ExecuteStream RPC, relaying
+// each StreamEvent frame onto the returned channel until the server ends
+// the stream or ctx is canceled.
+func (c *GRPCClient) ExecuteStream(ctx context.Context, req models.ExecuteRequest) (<-chan models.StreamEvent, error) {
+	desc := &grpc.StreamDesc{ServerStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, baseAgentService+"ExecuteStream", c.perRPCAuth())
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	out := make(chan models.StreamEvent)
+	go func() {
+		defer close(out)
+		for {
+			var wire grpcStreamEvent
+			if err := stream.RecvMsg(&wire); err != nil {
+				return
+			}
+			ev := models.StreamEvent{
+				Type:   wire.Type,
+				Delta:  wire.Delta,
+				Name:   wire.Name,
+				Output: wire.Output,
+				Error:  wire.Error,
+			}
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+			if ev.Type == "done" {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// grpcStreamEvent is the wire shape of proto/baseagent.proto's
+// StreamEvent message. It exists because models.StreamEvent.Type is
+// tagged json:"-": every other transport (SSE, WS) synthesizes Type in
+// Go code from a side channel (an "event: " line, a wsMessage.Type
+// field) rather than unmarshaling it directly, and gRPC follows the same
+// convention here.
+type grpcStreamEvent struct {
+	Type   string `json:"type,omitempty"`
+	Delta  string `json:"delta,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (c *GRPCClient) perRPCAuth() grpc.CallOption {
+	return grpc.PerRPCCredentials(tokenCreds{token: c.token})
+}
+
+// tokenCreds attaches the bearer token the same way HTTPClient.applyHeaders
+// and WSClient's dial header do, as per-RPC gRPC metadata instead of an
+// HTTP header.
+type tokenCreds struct {
+	token string
+}
+
+func (t tokenCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if t.token == "" {
+		return nil, nil
+	}
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCreds) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,20 @@
+package client
+
+import "encoding/json"
+
+// jsonCodec lets GRPCClient exchange the same JSON-tagged models types the
+// HTTP and WS transports already use, instead of requiring a protoc step
+// in a tree that has none.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
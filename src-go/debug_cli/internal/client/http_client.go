@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,9 +9,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
+	"github.com/tunapro1234/base-agent/src-go/netutil"
 )
 
 // HTTPClient talks to the Base Agent API.
@@ -18,14 +21,88 @@ type HTTPClient struct {
 	BaseURL string
 	Token   string
 	Client  *http.Client
+
+	deadlines *deadlineTimer
+}
+
+// Option configures an HTTPClient at construction time.
+type Option func(*HTTPClient)
+
+// WithMiddleware chains mw around the client's transport, outermost
+// first. Tests can use it to inject fakes without touching global state
+// like http.DefaultTransport.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *HTTPClient) {
+		c.Client.Transport = Chain(mw...)
+	}
 }
 
 // NewHTTPClient constructs a client.
-func NewHTTPClient(baseURL, token string) *HTTPClient {
-	return &HTTPClient{
-		BaseURL: baseURL,
-		Token:   token,
-		Client:  &http.Client{Timeout: 30 * time.Second},
+func NewHTTPClient(baseURL, token string, opts ...Option) *HTTPClient {
+	c := &HTTPClient{
+		BaseURL:   baseURL,
+		Token:     token,
+		Client:    &http.Client{},
+		deadlines: newDeadlineTimer(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetReadDeadline sets (or, with a zero Time, clears) the deadline by
+// which an in-flight response must finish being read. It may be called
+// at any time, including mid-request, and takes effect immediately.
+func (c *HTTPClient) SetReadDeadline(t time.Time) {
+	c.deadlines.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets (or, with a zero Time, clears) the deadline by
+// which an in-flight request must finish being sent. It may be called at
+// any time, including mid-request, and takes effect immediately.
+func (c *HTTPClient) SetWriteDeadline(t time.Time) {
+	c.deadlines.SetWriteDeadline(t)
+}
+
+// doRaw performs req, racing it against the configured read/write
+// deadlines so a call in flight can be aborted by a deadline set after it
+// started, not just one set up front via the request's context. On
+// success, ctx's cancel is deferred to the response body being closed
+// (via netutil.CancelOnCloseBody) rather than fired the instant doRaw returns -
+// canceling it eagerly would abort the body read the moment headers
+// arrived, which is fatal for streaming callers like ExecuteStream that
+// read resp.Body well after doRaw has returned.
+func (c *HTTPClient) doRaw(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := c.Client.Do(req)
+		done <- result{resp, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			cancel()
+			return nil, r.err
+		}
+		r.resp.Body = &netutil.CancelOnCloseBody{ReadCloser: r.resp.Body, Cancel: cancel}
+		return r.resp, nil
+	case <-c.deadlines.readDone():
+		cancel()
+		<-done
+		return nil, fmt.Errorf("read deadline exceeded")
+	case <-c.deadlines.writeDone():
+		cancel()
+		<-done
+		return nil, fmt.Errorf("write deadline exceeded")
 	}
 }
 
@@ -45,7 +122,7 @@ func (c *HTTPClient) Execute(ctx context.Context, req models.ExecuteRequest) (mo
 	}
 	c.applyHeaders(httpReq)
 
-	resp, err := c.Client.Do(httpReq)
+	resp, err := c.doRaw(httpReq)
 	if err != nil {
 		return models.ExecuteResponse{}, err
 	}
@@ -61,6 +138,83 @@ func (c *HTTPClient) Execute(ctx context.Context, req models.ExecuteRequest) (mo
 	return out, nil
 }
 
+// ExecuteStream calls POST /execute/stream and returns a channel of SSE
+// frames as they arrive, closed when the stream ends, ctx is canceled, or
+// the connection errors out partway through. It returns
+// ErrStreamingUnsupported before returning a channel if the server
+// doesn't expose the streaming endpoint, so callers can fall back to
+// Execute.
+func (c *HTTPClient) ExecuteStream(ctx context.Context, req models.ExecuteRequest) (<-chan models.StreamEvent, error) {
+	endpoint, err := c.resolve("/execute/stream")
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	c.applyHeaders(httpReq)
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.doRaw(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrStreamingUnsupported
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("http %d: %s", resp.StatusCode, string(body))
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body.Close()
+		return nil, ErrStreamingUnsupported
+	}
+
+	out := make(chan models.StreamEvent)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		var eventName string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				eventName = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				var ev models.StreamEvent
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+					continue
+				}
+				ev.Type = eventName
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+				if eventName == "done" {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// ErrStreamingUnsupported is returned by ExecuteStream when the server
+// doesn't support the streaming endpoint.
+var ErrStreamingUnsupported = fmt.Errorf("server does not support streaming")
+
 // ListTasks calls GET /tasks.
 func (c *HTTPClient) ListTasks(ctx context.Context, limit int) ([]models.TaskInfo, error) {
 	endpoint, err := c.resolve("/tasks")
@@ -83,7 +237,7 @@ func (c *HTTPClient) ListTasks(ctx context.Context, limit int) ([]models.TaskInf
 	}
 	c.applyHeaders(httpReq)
 
-	resp, err := c.Client.Do(httpReq)
+	resp, err := c.doRaw(httpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +255,65 @@ func (c *HTTPClient) ListTasks(ctx context.Context, limit int) ([]models.TaskInf
 	return payload.Tasks, nil
 }
 
+// Keys calls GET /keys, reporting every registered provider's API key
+// rotation health.
+func (c *HTTPClient) Keys(ctx context.Context) (map[string][]models.KeyState, error) {
+	endpoint, err := c.resolve("/keys")
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.applyHeaders(httpReq)
+
+	resp, err := c.doRaw(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http %d: %s", resp.StatusCode, string(body))
+	}
+	var payload struct {
+		Keys map[string][]models.KeyState `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Keys, nil
+}
+
+// ResumeTask calls POST /tasks/{id}/resume.
+func (c *HTTPClient) ResumeTask(ctx context.Context, id string) (models.ExecuteResponse, error) {
+	endpoint, err := c.resolve("/tasks/" + id + "/resume")
+	if err != nil {
+		return models.ExecuteResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return models.ExecuteResponse{}, err
+	}
+	c.applyHeaders(httpReq)
+
+	resp, err := c.doRaw(httpReq)
+	if err != nil {
+		return models.ExecuteResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return models.ExecuteResponse{}, fmt.Errorf("http %d: %s", resp.StatusCode, string(body))
+	}
+	var out models.ExecuteResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return models.ExecuteResponse{}, err
+	}
+	return out, nil
+}
+
 func (c *HTTPClient) resolve(path string) (string, error) {
 	base, err := url.Parse(c.BaseURL)
 	if err != nil {
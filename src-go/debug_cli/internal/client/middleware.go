@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RoundTripper mirrors http.RoundTripper so middlewares can be described
+// and tested without every caller needing to import net/http.
+type RoundTripper interface {
+	RoundTrip(req *http.Request) (*http.Response, error)
+}
+
+// RoundTripperFunc adapts a plain function to a RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a RoundTripper to add cross-cutting behavior (logging,
+// retries, rate limiting) around a request without HTTPClient's call
+// sites knowing it's there.
+type Middleware func(next RoundTripper) RoundTripper
+
+// Chain composes mw around http.DefaultTransport, outermost first: mw[0]
+// sees the request before mw[1], and sees the response after it.
+func Chain(mw ...Middleware) http.RoundTripper {
+	var rt RoundTripper = RoundTripperFunc(http.DefaultTransport.RoundTrip)
+	for i := len(mw) - 1; i >= 0; i-- {
+		rt = mw[i](rt)
+	}
+	return rt
+}
+
+// LoggingMiddleware prints a line per request and response when debug is
+// true, redacting the Authorization header. debug is read on every call
+// rather than captured once, so toggling /debug mid-session takes effect
+// immediately.
+func LoggingMiddleware(out io.Writer, debug func() bool) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !debug() {
+				return next.RoundTrip(req)
+			}
+			fmt.Fprintf(out, "--> %s %s\n", req.Method, req.URL)
+			if req.Header.Get("Authorization") != "" {
+				fmt.Fprintln(out, "    Authorization: [redacted]")
+			}
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				fmt.Fprintf(out, "<-- error: %v (%s)\n", err, time.Since(start))
+				return resp, err
+			}
+			fmt.Fprintf(out, "<-- %s (%s)\n", resp.Status, time.Since(start))
+			return resp, nil
+		})
+	}
+}
+
+// RetryMiddleware retries a request up to maxAttempts times on a network
+// error or a 429/503 response, honoring the response's Retry-After header
+// when present and otherwise backing off with jittered exponential delay.
+func RetryMiddleware(maxAttempts int) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+			for attempt := 0; attempt < maxAttempts; attempt++ {
+				if attempt > 0 {
+					select {
+					case <-time.After(retryDelay(attempt, resp)):
+					case <-req.Context().Done():
+						return nil, req.Context().Err()
+					}
+				}
+
+				attemptReq := req
+				if attempt > 0 && req.GetBody != nil {
+					body, bodyErr := req.GetBody()
+					if bodyErr != nil {
+						return nil, bodyErr
+					}
+					attemptReq = req.Clone(req.Context())
+					attemptReq.Body = body
+				}
+
+				resp, err = next.RoundTrip(attemptReq)
+				if err != nil {
+					continue
+				}
+				if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+					return resp, nil
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// retryDelay honors a Retry-After header (seconds or HTTP-date) when resp
+// carries one, otherwise backs off exponentially with full jitter.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if raw := resp.Header.Get("Retry-After"); raw != "" {
+			if secs, err := strconv.Atoi(raw); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+			if at, err := http.ParseTime(raw); err == nil {
+				if d := time.Until(at); d > 0 {
+					return d
+				}
+			}
+		}
+	}
+	base := time.Duration(1<<uint(attempt-1)) * 200 * time.Millisecond
+	if base > 5*time.Second {
+		base = 5 * time.Second
+	}
+	return time.Duration(rand.Int63n(int64(base) + 1))
+}
+
+// RateLimitMiddleware caps outbound requests to ratePerSecond per
+// destination host, with bursts up to burst tokens.
+func RateLimitMiddleware(ratePerSecond float64, burst int) Middleware {
+	limiter := &hostLimiter{buckets: map[string]*tokenBucket{}, rate: ratePerSecond, burst: burst}
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := limiter.wait(req.Context(), req.URL.Host); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// hostLimiter keeps one token bucket per destination host.
+type hostLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	rate    float64
+	burst   int
+}
+
+func (h *hostLimiter) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	b, ok := h.buckets[host]
+	if !ok {
+		b = &tokenBucket{tokens: float64(h.burst), rate: h.rate, burst: h.burst, last: time.Now()}
+		h.buckets[host] = b
+	}
+	h.mu.Unlock()
+	return b.wait(ctx)
+}
+
+// tokenBucket is a simple refilling token bucket, refilled lazily on each
+// call rather than by a background goroutine.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	rate   float64
+	burst  int
+	last   time.Time
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RequestIDMiddleware tags every outbound request with a unique
+// X-Request-Id header and, on failure, wraps the error in a RequestError
+// so callers (render.Error in particular) can surface the id alongside
+// the failure.
+func RequestIDMiddleware() Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			id := fmt.Sprintf("req_%d", time.Now().UnixNano())
+			req.Header.Set("X-Request-Id", id)
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return resp, &RequestError{ID: id, Err: err}
+			}
+			return resp, nil
+		})
+	}
+}
+
+// RequestError wraps a transport error with the X-Request-Id assigned to
+// the request that failed, so its Error() string (printed verbatim by
+// render.Error) carries the id for correlating against server logs.
+type RequestError struct {
+	ID  string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("[%s] %v", e.ID, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
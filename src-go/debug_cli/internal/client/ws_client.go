@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
+)
+
+// ErrWSUnsupported is returned by DialWSClient when the server doesn't
+// expose the /ws endpoint, so callers can fall back to HTTPClient.
+var ErrWSUnsupported = fmt.Errorf("server does not support the websocket transport")
+
+// wsMessage mirrors the AgentServer /ws protocol frame shape.
+type wsMessage struct {
+	Type         string   `json:"type"`
+	TaskID       string   `json:"task_id,omitempty"`
+	Instruction  string   `json:"instruction,omitempty"`
+	SystemPrompt string   `json:"system_prompt,omitempty"`
+	Provider     string   `json:"provider,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+	Delta        string   `json:"delta,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Output       string   `json:"output,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Success      bool     `json:"success,omitempty"`
+}
+
+// WSClient talks to AgentServer's /ws endpoint, keeping one long-lived
+// connection open across many REPL turns instead of one HTTP round trip
+// per turn.
+type WSClient struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// DialWSClient upgrades baseURL to a /ws connection. It returns
+// ErrWSUnsupported if the server responds 404, so callers can fall back to
+// HTTPClient.
+func DialWSClient(baseURL, token string) (*WSClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/ws"
+
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	conn, resp, err := websocket.DefaultDialer.Dial(u.String(), header)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrWSUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &WSClient{conn: conn}, nil
+}
+
+// Execute runs one turn over the open connection, invoking onEvent for
+// every token/tool_call/tool_result frame, and returns once a "done" frame
+// arrives. If ctx is canceled first, Execute sends a "cancel" frame so the
+// server aborts the in-flight agent.Execute, drains until the server
+// confirms, and returns ctx.Err().
+func (c *WSClient) Execute(ctx context.Context, req models.ExecuteRequest, onEvent func(models.StreamEvent)) (models.ExecuteResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := wsMessage{
+		Type:         "execute",
+		Instruction:  req.Instruction,
+		SystemPrompt: req.SystemPrompt,
+		Provider:     req.Provider,
+		Model:        req.Model,
+		Temperature:  req.Temperature,
+	}
+	if err := c.conn.WriteJSON(out); err != nil {
+		return models.ExecuteResponse{}, err
+	}
+
+	type frame struct {
+		msg wsMessage
+		err error
+	}
+	frames := make(chan frame, 1)
+	go func() {
+		defer close(frames)
+		for {
+			var m wsMessage
+			if err := c.conn.ReadJSON(&m); err != nil {
+				frames <- frame{err: err}
+				return
+			}
+			frames <- frame{msg: m}
+			if m.Type == "done" {
+				return
+			}
+		}
+	}()
+
+	var taskID string
+	for {
+		select {
+		case <-ctx.Done():
+			_ = c.conn.WriteJSON(wsMessage{Type: "cancel", TaskID: taskID})
+			for f := range frames {
+				if f.err != nil || f.msg.Type == "done" {
+					break
+				}
+			}
+			return models.ExecuteResponse{}, ctx.Err()
+		case f := <-frames:
+			if f.err != nil {
+				return models.ExecuteResponse{}, f.err
+			}
+			if taskID == "" {
+				taskID = f.msg.TaskID
+			}
+			switch f.msg.Type {
+			case "token":
+				onEvent(models.StreamEvent{Type: "token", Delta: f.msg.Delta})
+			case "tool_call":
+				onEvent(models.StreamEvent{Type: "tool_call", Name: f.msg.Name})
+			case "tool_result":
+				onEvent(models.StreamEvent{Type: "tool_result", Name: f.msg.Name, Output: f.msg.Output, Error: f.msg.Error})
+			case "done":
+				return models.ExecuteResponse{
+					Success: f.msg.Success,
+					Output:  f.msg.Output,
+					TaskID:  f.msg.TaskID,
+					Error:   f.msg.Error,
+				}, nil
+			}
+		}
+	}
+}
+
+// Close closes the underlying connection.
+func (c *WSClient) Close() error {
+	return c.conn.Close()
+}
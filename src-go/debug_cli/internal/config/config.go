@@ -0,0 +1,93 @@
+// Package config loads the debug CLI's optional YAML config file: a set of
+// named provider profiles plus an access-control block gating which slash
+// commands each role may run.
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
+)
+
+// Config is the root of a YAML config file loaded via -config or
+// BASE_AGENT_CONFIG.
+type Config struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+	AccessControl  AccessControl      `yaml:"access_control"`
+}
+
+// Profile is one named provider profile: base URL, auth, model allowlist,
+// and a temperature default, switchable at runtime with /profile <name>.
+// Fields left empty don't override whatever ParseConfig already resolved
+// from flags, env, or defaults. There's no api_keys field: the debug CLI is
+// a client of the agent's HTTP/WS/gRPC API and has no local key pool to wire
+// one into - API key rotation is a server-side concern (see llm.KeyPool).
+type Profile struct {
+	BaseURL     string   `yaml:"base_url"`
+	Token       string   `yaml:"token"`
+	Provider    string   `yaml:"provider"`
+	Model       string   `yaml:"model"`
+	Models      []string `yaml:"models"`
+	Temperature *float64 `yaml:"temperature"`
+}
+
+// ApplyTo overlays p's non-empty fields onto cfg. It's used both to seed
+// cfg from the file's default_profile at startup (before env and flags are
+// applied on top) and to switch profiles at runtime via /profile.
+func (p Profile) ApplyTo(cfg *models.CLIConfig) {
+	if p.BaseURL != "" {
+		cfg.BaseURL = p.BaseURL
+	}
+	if p.Token != "" {
+		cfg.Token = p.Token
+	}
+	if p.Provider != "" {
+		cfg.Provider = p.Provider
+	}
+	if p.Model != "" {
+		cfg.Model = p.Model
+	}
+	if p.Temperature != nil {
+		cfg.Temperature = *p.Temperature
+	}
+	// Unlike the fields above, an empty Models list is meaningful - it means
+	// this profile imposes no restriction - so it always overwrites, rather
+	// than leaving a previous profile's allowlist in effect.
+	cfg.ModelAllowlist = p.Models
+}
+
+// AccessControl maps named roles to the slash commands (without their
+// leading "/") they may run, modeled on the secretshop category/command ACL
+// pattern: a role whose command set contains "*" may run anything.
+type AccessControl struct {
+	Role  string              `yaml:"role"`
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// Allows reports whether role may run command. An unconfigured role, or one
+// with no matching entry, is denied.
+func (ac AccessControl) Allows(role, command string) bool {
+	for _, c := range ac.Roles[role] {
+		if c == "*" || c == command {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads and parses the YAML config file at path.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
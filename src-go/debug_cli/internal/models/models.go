@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // CLIConfig stores runtime settings.
 type CLIConfig struct {
 	BaseURL      string
@@ -9,6 +11,23 @@ type CLIConfig struct {
 	Temperature  float64
 	Debug        bool
 	Token        string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	Transport    string
+	Stream       bool
+	// ConfigPath is the YAML config file loaded at startup, empty if none
+	// was given.
+	ConfigPath string
+	// Profile is the active profile name from that file, empty if no
+	// config was loaded or it defines no default_profile.
+	Profile string
+	// ACLRole is the active access-control role from that file's
+	// access_control block, empty if the file defines none (in which case
+	// every slash command is allowed).
+	ACLRole string
+	// ModelAllowlist restricts which models /model may switch to, from the
+	// active profile's models list. Empty means unrestricted.
+	ModelAllowlist []string
 }
 
 // ChatMessage is a transcript entry.
@@ -36,6 +55,27 @@ type ExecuteResponse struct {
 	Error   string         `json:"error,omitempty"`
 }
 
+// StreamEvent is one SSE frame received from POST /execute/stream.
+type StreamEvent struct {
+	Type   string `json:"-"`
+	Delta  string `json:"delta,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// KeyState mirrors llm.KeyState, the per-key rotation health snapshot
+// served by GET /keys.
+type KeyState struct {
+	Key            string
+	Healthy        bool
+	LastUsed       time.Time
+	Failures       int
+	CooldownUntil  time.Time
+	QuotaRemaining float64
+	Breaker        string
+}
+
 // TaskInfo represents /tasks entries.
 type TaskInfo struct {
 	ID          string `json:"id"`
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
 )
@@ -13,6 +14,7 @@ func Banner(out io.Writer, cfg models.CLIConfig) {
 	fmt.Fprintln(out, "Base Agent Debug CLI")
 	fmt.Fprintf(out, "API: %s\n", cfg.BaseURL)
 	fmt.Fprintf(out, "Provider: %s  Model: %s  Temp: %.2f\n", cfg.Provider, cfg.Model, cfg.Temperature)
+	fmt.Fprintf(out, "Transport: %s\n", cfg.Transport)
 	fmt.Fprintln(out, "Type /help for commands.")
 }
 
@@ -27,11 +29,17 @@ func Help(out io.Writer) {
 	fmt.Fprintln(out, "  /temp <float>          Set temperature")
 	fmt.Fprintln(out, "  /debug [on|off]        Toggle debug output")
 	fmt.Fprintln(out, "  /tasks [limit]         List tasks")
+	fmt.Fprintln(out, "  /tasks resume <id>     Resume a checkpointed task")
+	fmt.Fprintln(out, "  /keys                  Show API key rotation health per provider")
 	fmt.Fprintln(out, "  /history               Show chat history")
 	fmt.Fprintln(out, "  /reset                 Clear chat history")
 	fmt.Fprintln(out, "  /config                Show current config")
+	fmt.Fprintln(out, "  /profile [name]        Switch provider profile, or list profiles")
 	fmt.Fprintln(out, "  /base <url>            Update base URL")
 	fmt.Fprintln(out, "  /token <token>         Update bearer token")
+	fmt.Fprintln(out, "  /timeout read <dur>    Set the read deadline, e.g. /timeout read 30s")
+	fmt.Fprintln(out, "  /timeout write <dur>   Set the write deadline, e.g. /timeout write 90s")
+	fmt.Fprintln(out, "  /cancel                Abort the request currently in flight")
 }
 
 // Response prints an execution response.
@@ -54,6 +62,96 @@ func Response(out io.Writer, resp models.ExecuteResponse, debug bool) {
 	}
 }
 
+// StreamToken prints an incremental token as it arrives, with no trailing
+// newline so subsequent tokens continue on the same line.
+func StreamToken(out io.Writer, delta string) {
+	fmt.Fprint(out, delta)
+}
+
+// StreamPrefix prints the leading "assistant> " prompt before the first
+// token of a streamed response.
+func StreamPrefix(out io.Writer) {
+	fmt.Fprint(out, "assistant> ")
+}
+
+// StreamEnd finishes a streamed response, printing the trailing newline
+// and, if debug is set, the final trace.
+func StreamEnd(out io.Writer, resp models.ExecuteResponse, debug bool) {
+	fmt.Fprintln(out)
+	if !resp.Success && resp.Error != "" {
+		fmt.Fprintf(out, "error: %s\n", resp.Error)
+	}
+	if debug && resp.Trace != nil {
+		fmt.Fprintln(out, "trace:")
+		keys := make([]string, 0, len(resp.Trace))
+		for key := range resp.Trace {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(out, "  %s: %v\n", key, resp.Trace[key])
+		}
+	}
+}
+
+// StreamResponse consumes a channel of SSE frames from
+// client.HTTPClient.ExecuteStream, printing each token as it arrives and
+// finishing with StreamEnd once the channel closes. It returns the
+// assembled response so callers can append it to history like any other
+// turn.
+func StreamResponse(out io.Writer, ch <-chan models.StreamEvent, debug bool) models.ExecuteResponse {
+	var resp models.ExecuteResponse
+	printedPrefix := false
+	for ev := range ch {
+		switch ev.Type {
+		case "token":
+			if !printedPrefix {
+				StreamPrefix(out)
+				printedPrefix = true
+			}
+			StreamToken(out, ev.Delta)
+		case "done":
+			resp = models.ExecuteResponse{Output: ev.Output, Error: ev.Error, Success: ev.Error == ""}
+		}
+	}
+	if printedPrefix {
+		StreamEnd(out, resp, debug)
+	} else {
+		Response(out, resp, debug)
+	}
+	return resp
+}
+
+// Keys prints each registered provider's API key rotation health, sorted by
+// provider name so output is stable across calls.
+func Keys(out io.Writer, keys map[string][]models.KeyState) {
+	if len(keys) == 0 {
+		fmt.Fprintln(out, "no keys")
+		return
+	}
+	providers := make([]string, 0, len(keys))
+	for p := range keys {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		fmt.Fprintf(out, "%s:\n", provider)
+		for _, k := range keys[provider] {
+			status := "healthy"
+			if !k.Healthy {
+				status = "disabled"
+			} else if time.Now().Before(k.CooldownUntil) {
+				status = fmt.Sprintf("cooling_down (until %s)", k.CooldownUntil.Format(time.RFC3339))
+			}
+			fmt.Fprintf(out, "  %s  %s  failures=%d  quota=%.2f", k.Key, status, k.Failures, k.QuotaRemaining)
+			if k.Breaker != "" {
+				fmt.Fprintf(out, "  breaker=%s", k.Breaker)
+			}
+			fmt.Fprintln(out)
+		}
+	}
+}
+
 // Tasks prints task list.
 func Tasks(out io.Writer, tasks []models.TaskInfo) {
 	if len(tasks) == 0 {
@@ -73,6 +171,12 @@ func Config(out io.Writer, cfg models.CLIConfig) {
 	fmt.Fprintf(out, "  model: %s\n", cfg.Model)
 	fmt.Fprintf(out, "  temp: %.2f\n", cfg.Temperature)
 	fmt.Fprintf(out, "  debug: %v\n", cfg.Debug)
+	fmt.Fprintf(out, "  read timeout: %s  write timeout: %s\n", cfg.ReadTimeout, cfg.WriteTimeout)
+	fmt.Fprintf(out, "  transport: %s\n", cfg.Transport)
+	fmt.Fprintf(out, "  stream: %v\n", cfg.Stream)
+	if cfg.Profile != "" || cfg.ACLRole != "" {
+		fmt.Fprintf(out, "  profile: %s  acl role: %s\n", cfg.Profile, cfg.ACLRole)
+	}
 	if cfg.SystemPrompt != "" {
 		fmt.Fprintf(out, "  system: %s\n", cfg.SystemPrompt)
 	}
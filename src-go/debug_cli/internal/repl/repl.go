@@ -6,30 +6,58 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/client"
+	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/config"
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/models"
 	"github.com/tunapro1234/base-agent/src-go/debug_cli/internal/render"
 )
 
 // REPL provides an interactive CLI loop.
 type REPL struct {
-	Config  models.CLIConfig
-	Client  *client.HTTPClient
+	Config models.CLIConfig
+	Client *client.HTTPClient
+	// Backend is the transport command handlers dispatch through. It
+	// defaults to Client (HTTP) and is swapped for a GRPCClient when
+	// Config.Transport is "grpc". WS takes priority over both when set,
+	// since it's a stateful connection rather than a per-call Backend.
+	Backend client.Backend
+	WS      *client.WSClient
 	History []models.ChatMessage
 	In      io.Reader
 	Out     io.Writer
+
+	// ClientOpts are the Options the initial HTTP client was built with
+	// (middleware, mainly). Every later rebuild - /base, /token, /profile -
+	// reapplies them so reconnecting doesn't silently drop retries, rate
+	// limiting, or logging.
+	ClientOpts []client.Option
+
+	// FileConfig is the parsed YAML config file named by Config.ConfigPath,
+	// holding the full profile catalog and access control rules so /profile
+	// can switch profiles and handleCommand can enforce the active ACL role.
+	// Zero value if no config file was loaded.
+	FileConfig config.Config
+
+	lines chan string
+
+	mu           sync.Mutex
+	activeCancel context.CancelFunc
 }
 
 // New constructs a REPL instance.
 func New(cfg models.CLIConfig, clientInstance *client.HTTPClient) *REPL {
-	return &REPL{Config: cfg, Client: clientInstance, In: os.Stdin, Out: os.Stdout}
+	return &REPL{Config: cfg, Client: clientInstance, Backend: clientInstance, In: os.Stdin, Out: os.Stdout}
 }
 
-// Run starts the interactive loop.
+// Run starts the interactive loop. Input is read on a background goroutine
+// into r.lines so that a turn in flight (see send) can keep listening for
+// a "/cancel" typed while it's still waiting on the server.
 func (r *REPL) Run() {
 	if r.In == nil {
 		r.In = os.Stdin
@@ -38,17 +66,56 @@ func (r *REPL) Run() {
 		r.Out = os.Stdout
 	}
 	if r.Client == nil {
-		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token)
+		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token, r.ClientOpts...)
+	}
+	if r.Backend == nil {
+		r.Backend = r.Client
+	}
+	if r.Config.ConfigPath != "" {
+		fileCfg, err := config.Load(r.Config.ConfigPath)
+		if err != nil {
+			render.Error(r.Out, err)
+		} else {
+			r.FileConfig = fileCfg
+		}
 	}
 
 	render.Banner(r.Out, r.Config)
-	scanner := bufio.NewScanner(r.In)
+
+	// signal.Notify takes over SIGINT delivery, so Go's default
+	// terminate-the-process behavior no longer applies: we must cancel
+	// the in-flight request (if any) or exit explicitly ourselves.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		for range sigCh {
+			if r.cancelActive() {
+				render.Info(r.Out, "canceled")
+				continue
+			}
+			os.Exit(130)
+		}
+	}()
+
+	var scanErr error
+	r.lines = make(chan string)
+	go func() {
+		defer close(r.lines)
+		scanner := bufio.NewScanner(r.In)
+		for scanner.Scan() {
+			r.lines <- scanner.Text()
+		}
+		scanErr = scanner.Err()
+	}()
+
 	for {
 		fmt.Fprint(r.Out, "> ")
-		if !scanner.Scan() {
+		line, ok := <-r.lines
+		if !ok {
 			break
 		}
-		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
@@ -60,8 +127,8 @@ func (r *REPL) Run() {
 		}
 		r.send(line)
 	}
-	if err := scanner.Err(); err != nil {
-		render.Error(r.Out, err)
+	if scanErr != nil {
+		render.Error(r.Out, scanErr)
 	}
 }
 
@@ -70,6 +137,11 @@ func (r *REPL) handleCommand(line string) bool {
 	cmd := strings.TrimPrefix(fields[0], "/")
 	args := strings.TrimSpace(strings.TrimPrefix(line, "/"+cmd))
 
+	if len(r.FileConfig.AccessControl.Roles) > 0 && !r.FileConfig.AccessControl.Allows(r.Config.ACLRole, cmd) {
+		render.Error(r.Out, fmt.Errorf("command /%s not permitted for role %q", cmd, r.Config.ACLRole))
+		return false
+	}
+
 	switch cmd {
 	case "exit", "quit":
 		return true
@@ -94,7 +166,12 @@ func (r *REPL) handleCommand(line string) bool {
 			render.Info(r.Out, fmt.Sprintf("model: %s", r.Config.Model))
 			return false
 		}
-		r.Config.Model = strings.TrimSpace(args)
+		model := strings.TrimSpace(args)
+		if !r.modelAllowed(model) {
+			render.Error(r.Out, fmt.Errorf("model %q not in profile allowlist: %s", model, strings.Join(r.Config.ModelAllowlist, ", ")))
+			return false
+		}
+		r.Config.Model = model
 		render.Info(r.Out, "model updated")
 	case "temp":
 		if args == "" {
@@ -122,6 +199,11 @@ func (r *REPL) handleCommand(line string) bool {
 		r.Config.Debug = flag
 		render.Info(r.Out, fmt.Sprintf("debug: %v", r.Config.Debug))
 	case "tasks":
+		fields := strings.Fields(args)
+		if len(fields) == 2 && fields[0] == "resume" {
+			r.resumeTask(fields[1])
+			return false
+		}
 		limit := 10
 		if args != "" {
 			if val, err := strconv.Atoi(strings.TrimSpace(args)); err == nil {
@@ -129,6 +211,8 @@ func (r *REPL) handleCommand(line string) bool {
 			}
 		}
 		r.listTasks(limit)
+	case "keys":
+		r.showKeys()
 	case "history":
 		render.History(r.Out, r.History)
 	case "reset":
@@ -142,7 +226,10 @@ func (r *REPL) handleCommand(line string) bool {
 			return false
 		}
 		r.Config.BaseURL = strings.TrimSpace(args)
-		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token)
+		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token, r.ClientOpts...)
+		if r.Config.Transport != "grpc" {
+			r.Backend = r.Client
+		}
 		render.Info(r.Out, "base url updated")
 	case "token":
 		if args == "" {
@@ -150,19 +237,134 @@ func (r *REPL) handleCommand(line string) bool {
 			return false
 		}
 		r.Config.Token = strings.TrimSpace(args)
-		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token)
+		r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token, r.ClientOpts...)
+		if r.Config.Transport != "grpc" {
+			r.Backend = r.Client
+		}
 		render.Info(r.Out, "token updated")
+	case "profile":
+		r.switchProfile(strings.TrimSpace(args))
+	case "timeout":
+		r.handleTimeout(args)
+	case "cancel":
+		if !r.cancelActive() {
+			render.Info(r.Out, "no request in flight")
+		}
 	default:
 		render.Info(r.Out, "unknown command, type /help")
 	}
 	return false
 }
 
+// switchProfile applies the named profile from the loaded config file,
+// rebuilding the HTTP client since the profile may change the base URL or
+// token, and prints the active profiles list if called with no name.
+func (r *REPL) switchProfile(name string) {
+	if len(r.FileConfig.Profiles) == 0 {
+		render.Error(r.Out, fmt.Errorf("no config file loaded, nothing to switch"))
+		return
+	}
+	if name == "" {
+		names := make([]string, 0, len(r.FileConfig.Profiles))
+		for p := range r.FileConfig.Profiles {
+			names = append(names, p)
+		}
+		render.Info(r.Out, fmt.Sprintf("active: %s  available: %s", r.Config.Profile, strings.Join(names, ", ")))
+		return
+	}
+	profile, ok := r.FileConfig.Profiles[name]
+	if !ok {
+		render.Error(r.Out, fmt.Errorf("unknown profile: %s", name))
+		return
+	}
+	profile.ApplyTo(&r.Config)
+	r.Config.Profile = name
+	r.Client = client.NewHTTPClient(r.Config.BaseURL, r.Config.Token, r.ClientOpts...)
+	if r.Config.Transport != "grpc" {
+		r.Backend = r.Client
+	}
+	render.Info(r.Out, fmt.Sprintf("profile: %s", name))
+}
+
+// handleTimeout implements /timeout read <duration> and /timeout write
+// <duration>, re-arming the matching deadline on the active client
+// immediately so it applies even to a request already in flight.
+func (r *REPL) handleTimeout(args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		render.Info(r.Out, fmt.Sprintf("read: %s  write: %s", r.Config.ReadTimeout, r.Config.WriteTimeout))
+		return
+	}
+	if len(fields) != 2 {
+		render.Error(r.Out, fmt.Errorf("usage: /timeout <read|write> <duration>"))
+		return
+	}
+	dur, err := time.ParseDuration(fields[1])
+	if err != nil {
+		render.Error(r.Out, err)
+		return
+	}
+	switch fields[0] {
+	case "read":
+		r.Config.ReadTimeout = dur
+		r.Client.SetReadDeadline(time.Now().Add(dur))
+		render.Info(r.Out, fmt.Sprintf("read timeout: %s", dur))
+	case "write":
+		r.Config.WriteTimeout = dur
+		r.Client.SetWriteDeadline(time.Now().Add(dur))
+		render.Info(r.Out, fmt.Sprintf("write timeout: %s", dur))
+	default:
+		render.Error(r.Out, fmt.Errorf("usage: /timeout <read|write> <duration>"))
+	}
+}
+
+// send runs one turn in the background and, while it's in flight, keeps
+// reading r.lines so the user can type "/cancel" to abort it mid-response
+// instead of waiting for the prompt to return.
 func (r *REPL) send(line string) {
 	r.History = append(r.History, models.ChatMessage{Role: "user", Content: line})
-	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
-	defer cancel()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.activeCancel = cancel
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.activeCancel = nil
+		r.mu.Unlock()
+	}()
+
+	done := make(chan models.ExecuteResponse, 1)
+	go func() {
+		done <- r.doSend(ctx, line)
+	}()
+
+	for {
+		select {
+		case resp := <-done:
+			if resp.Output != "" {
+				r.History = append(r.History, models.ChatMessage{Role: "assistant", Content: resp.Output})
+			}
+			return
+		case next, ok := <-r.lines:
+			if !ok {
+				cancel()
+				<-done
+				return
+			}
+			if strings.TrimSpace(next) == "/cancel" {
+				render.Info(r.Out, "canceling...")
+				cancel()
+				continue
+			}
+			render.Info(r.Out, "busy, type /cancel to abort")
+		}
+	}
+}
+
+// doSend performs the actual round trip, over WS if connected or HTTP
+// otherwise, and is the unit of work canceled by send's "/cancel" handling.
+func (r *REPL) doSend(ctx context.Context, line string) models.ExecuteResponse {
 	temp := r.Config.Temperature
 	req := models.ExecuteRequest{
 		Instruction:  line,
@@ -172,22 +374,98 @@ func (r *REPL) send(line string) {
 		Temperature:  &temp,
 		Debug:        r.Config.Debug,
 	}
-	resp, err := r.Client.Execute(ctx, req)
+
+	if r.WS != nil {
+		return r.sendWS(ctx, req)
+	}
+
+	r.Client.SetWriteDeadline(time.Now().Add(r.Config.WriteTimeout))
+	r.Client.SetReadDeadline(time.Now().Add(r.Config.ReadTimeout))
+
+	resp, ok := r.sendStream(ctx, req)
+	if ok {
+		return resp
+	}
+	resp, err := r.Backend.Execute(ctx, req)
 	if err != nil {
-		render.Error(r.Out, err)
+		r.renderRequestErr(ctx, err)
+		return models.ExecuteResponse{}
+	}
+	render.Response(r.Out, resp, r.Config.Debug)
+	return resp
+}
+
+// sendWS runs a turn over the long-lived /ws connection, rendering tokens
+// as they arrive just like sendStream does for HTTP.
+func (r *REPL) sendWS(ctx context.Context, req models.ExecuteRequest) models.ExecuteResponse {
+	printedPrefix := false
+	resp, err := r.WS.Execute(ctx, req, func(ev models.StreamEvent) {
+		if ev.Type != "token" {
+			return
+		}
+		if !printedPrefix {
+			render.StreamPrefix(r.Out)
+			printedPrefix = true
+		}
+		render.StreamToken(r.Out, ev.Delta)
+	})
+	if err != nil {
+		r.renderRequestErr(ctx, err)
+		return models.ExecuteResponse{}
+	}
+	if printedPrefix {
+		render.StreamEnd(r.Out, resp, r.Config.Debug)
+	} else {
+		render.Response(r.Out, resp, r.Config.Debug)
+	}
+	return resp
+}
+
+// renderRequestErr prints "canceled" instead of a raw context error when
+// the request was aborted via /cancel, matching the style of the rest of
+// the REPL's terse status lines.
+func (r *REPL) renderRequestErr(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		render.Info(r.Out, "canceled")
 		return
 	}
-	if resp.Output != "" {
-		r.History = append(r.History, models.ChatMessage{Role: "assistant", Content: resp.Output})
+	render.Error(r.Out, err)
+}
+
+// cancelActive aborts the currently in-flight request, if any, reporting
+// whether one was found.
+func (r *REPL) cancelActive() bool {
+	r.mu.Lock()
+	cancel := r.activeCancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return false
 	}
-	render.Response(r.Out, resp, r.Config.Debug)
+	cancel()
+	return true
 }
 
-func (r *REPL) listTasks(limit int) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+// sendStream attempts the streaming endpoint, rendering tokens as they
+// arrive. It reports ok=false when streaming isn't available, or disabled
+// via Config.Stream, so the caller can fall back to the buffered path.
+func (r *REPL) sendStream(ctx context.Context, req models.ExecuteRequest) (models.ExecuteResponse, bool) {
+	if !r.Config.Stream {
+		return models.ExecuteResponse{}, false
+	}
+	ch, err := r.Backend.ExecuteStream(ctx, req)
+	if err == client.ErrStreamingUnsupported {
+		return models.ExecuteResponse{}, false
+	}
+	if err != nil {
+		render.Error(r.Out, err)
+		return models.ExecuteResponse{}, true
+	}
+	return render.StreamResponse(r.Out, ch, r.Config.Debug), true
+}
 
-	tasks, err := r.Client.ListTasks(ctx, limit)
+func (r *REPL) listTasks(limit int) {
+	r.Client.SetReadDeadline(time.Now().Add(r.Config.ReadTimeout))
+	tasks, err := r.Backend.ListTasks(context.Background(), limit)
 	if err != nil {
 		render.Error(r.Out, err)
 		return
@@ -195,6 +473,45 @@ func (r *REPL) listTasks(limit int) {
 	render.Tasks(r.Out, tasks)
 }
 
+// showKeys fetches and renders every provider's API key rotation health via
+// GET /keys. Like resumeTask, it's HTTP-only since it's an admin endpoint
+// outside the Backend/proto contract.
+func (r *REPL) showKeys() {
+	r.Client.SetReadDeadline(time.Now().Add(r.Config.ReadTimeout))
+	keys, err := r.Client.Keys(context.Background())
+	if err != nil {
+		render.Error(r.Out, err)
+		return
+	}
+	render.Keys(r.Out, keys)
+}
+
+// resumeTask continues a checkpointed task via POST /tasks/{id}/resume and
+// renders the result like any other response.
+func (r *REPL) resumeTask(id string) {
+	r.Client.SetReadDeadline(time.Now().Add(r.Config.ReadTimeout))
+	resp, err := r.Client.ResumeTask(context.Background(), id)
+	if err != nil {
+		render.Error(r.Out, err)
+		return
+	}
+	render.Response(r.Out, resp, r.Config.Debug)
+}
+
+// modelAllowed reports whether model may be selected via /model, per the
+// active profile's Models allowlist. An empty allowlist permits anything.
+func (r *REPL) modelAllowed(model string) bool {
+	if len(r.Config.ModelAllowlist) == 0 {
+		return true
+	}
+	for _, m := range r.Config.ModelAllowlist {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
 func parseOnOff(value string) (bool, error) {
 	switch strings.ToLower(strings.TrimSpace(value)) {
 	case "on", "true", "1", "yes":
@@ -6,8 +6,36 @@ import (
 	"strings"
 
 	"github.com/tunapro1234/base-agent/src-go/llm"
+	"github.com/tunapro1234/base-agent/src-go/task"
 )
 
+// buildTaskStore selects the task store backend named by cfg.TaskStore
+// ("memory", "sqlite", or "bolt"; "memory" is the default), opening
+// cfg.TaskDB as the backing file/DSN for the persistent backends.
+func buildTaskStore(cfg AgentConfig) (task.Store, error) {
+	if !cfg.EnableTaskStore {
+		return nil, nil
+	}
+	switch cfg.TaskStore {
+	case "", "memory":
+		return task.NewTaskStore(false, ""), nil
+	case "sqlite":
+		store, err := task.NewSQLiteStore(cfg.TaskDB)
+		if err != nil {
+			return nil, fmt.Errorf("build task store: %w", err)
+		}
+		return store, nil
+	case "bolt":
+		store, err := task.NewBoltStore(cfg.TaskDB)
+		if err != nil {
+			return nil, fmt.Errorf("build task store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown task store backend: %s", cfg.TaskStore)
+	}
+}
+
 func loadKeysFromEnv(primary string, extrasPrefix string) []string {
 	keys := []string{}
 	if raw := os.Getenv(primary); raw != "" {
@@ -62,10 +90,19 @@ func buildLLMRouter(cfg AgentConfig) (*llm.LLMRouter, error) {
 			model = "gemini-3-flash-preview"
 		}
 		router.RegisterProvider("gemini", llm.NewGeminiAdapter(llm.GeminiConfig{
-			APIKeys:     keys,
-			BaseURL:     "https://generativelanguage.googleapis.com",
-			Model:       model,
-			Temperature: config.Temperature,
+			APIKeys:           keys,
+			BaseURL:           "https://generativelanguage.googleapis.com",
+			Model:             model,
+			Temperature:       config.Temperature,
+			RotationStrategy:  config.RotationStrategy,
+			StreamIdleTimeout: config.StreamIdleTimeout,
+			Retry: llm.RetryPolicy{
+				MaxAttempts: config.RetryMaxAttempts,
+				BaseDelay:   config.RetryBaseDelay,
+				MaxDelay:    config.RetryMaxDelay,
+			},
+			BreakerThreshold: config.BreakerThreshold,
+			BreakerCooldown:  config.BreakerCooldown,
 		}))
 	} else if config.Provider == "gemini" {
 		return nil, fmt.Errorf("gemini provider selected but no GEMINI_API_KEY found")
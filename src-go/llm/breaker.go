@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreakerState is one API key's circuit breaker state.
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String returns the lowercase, metrics/JSON-friendly name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+const defaultBreakerThreshold = 5
+const defaultBreakerCooldown = 30 * time.Second
+
+// CircuitBreaker tracks, per key, consecutive failures and trips open once
+// Threshold is reached, refusing further attempts until Cooldown elapses.
+// After the cooldown it allows exactly one half-open probe; a successful
+// probe closes the breaker, a failed one reopens it for another cooldown.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu   sync.Mutex
+	keys map[string]*breakerEntry
+}
+
+type breakerEntry struct {
+	consecutiveFailures int
+	openUntil           time.Time
+	probing             bool
+}
+
+// NewCircuitBreaker creates a CircuitBreaker that opens after threshold
+// consecutive failures and cools down for the given duration. A
+// non-positive threshold or cooldown falls back to the package defaults.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown, keys: map[string]*breakerEntry{}}
+}
+
+func (b *CircuitBreaker) threshold() int {
+	if b.Threshold <= 0 {
+		return defaultBreakerThreshold
+	}
+	return b.Threshold
+}
+
+func (b *CircuitBreaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return defaultBreakerCooldown
+	}
+	return b.Cooldown
+}
+
+func (b *CircuitBreaker) entry(key string) *breakerEntry {
+	if b.keys == nil {
+		b.keys = map[string]*breakerEntry{}
+	}
+	e, ok := b.keys[key]
+	if !ok {
+		e = &breakerEntry{}
+		b.keys[key] = e
+	}
+	return e
+}
+
+// Allow reports whether key may be tried right now. A key that has
+// reached its failure threshold is refused until its cooldown elapses, at
+// which point exactly one caller is let through as a half-open probe.
+func (b *CircuitBreaker) Allow(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := b.entry(key)
+	if e.consecutiveFailures < b.threshold() {
+		return true
+	}
+	if time.Now().Before(e.openUntil) {
+		return false
+	}
+	if e.probing {
+		return false
+	}
+	e.probing = true
+	return true
+}
+
+// RecordSuccess closes the breaker for key.
+func (b *CircuitBreaker) RecordSuccess(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := b.entry(key)
+	e.consecutiveFailures = 0
+	e.openUntil = time.Time{}
+	e.probing = false
+}
+
+// RecordFailure counts a failure for key, tripping the breaker open once
+// Threshold consecutive failures have been recorded.
+func (b *CircuitBreaker) RecordFailure(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := b.entry(key)
+	e.consecutiveFailures++
+	e.probing = false
+	if e.consecutiveFailures >= b.threshold() {
+		e.openUntil = time.Now().Add(b.cooldown())
+	}
+}
+
+// State reports key's current breaker state.
+func (b *CircuitBreaker) State(key string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := b.entry(key)
+	if e.consecutiveFailures < b.threshold() {
+		return BreakerClosed
+	}
+	if time.Now().Before(e.openUntil) {
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+// AllOpen reports whether every one of keys is currently open, meaning the
+// provider has no healthy key left to try.
+func (b *CircuitBreaker) AllOpen(keys []string) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	for _, k := range keys {
+		if b.State(k) != BreakerOpen {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrAllKeysUnhealthy is returned by a provider adapter when every
+// configured API key's circuit breaker is open, so the router has no key
+// left to dispatch to. The HTTP layer maps it to 503.
+type ErrAllKeysUnhealthy struct {
+	Provider string
+}
+
+func (e ErrAllKeysUnhealthy) Error() string {
+	return fmt.Sprintf("%s: all API keys unhealthy", e.Provider)
+}
@@ -1,31 +1,65 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/netutil"
 	"github.com/tunapro1234/base-agent/src-go/tools"
 )
 
 // GeminiConfig configures the Gemini adapter.
 type GeminiConfig struct {
-	APIKeys     []string
-	BaseURL     string
-	Model       string
-	Temperature float64
+	APIKeys          []string
+	BaseURL          string
+	Model            string
+	Temperature      float64
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	RotationStrategy string
+	// StreamIdleTimeout bounds how long Stream waits between SSE chunks
+	// before giving up on a stalled provider. Defaults to
+	// defaultStreamIdleTimeout.
+	StreamIdleTimeout time.Duration
+	// Retry controls how many times, and with what backoff, Complete
+	// retries a failed request against a different key. Zero value uses
+	// DefaultRetryPolicy.
+	Retry RetryPolicy
+	// BreakerThreshold is the number of consecutive failures a key
+	// tolerates before Complete stops trying it. Zero uses
+	// defaultBreakerThreshold.
+	BreakerThreshold int
+	// BreakerCooldown is how long a tripped key is skipped before a
+	// half-open probe is allowed. Zero uses defaultBreakerCooldown.
+	BreakerCooldown time.Duration
 }
 
+const defaultGeminiTimeout = 60 * time.Second
+const defaultStreamIdleTimeout = 30 * time.Second
+
 // GeminiAdapter implements Gemini REST calls.
 type GeminiAdapter struct {
-	cfg     GeminiConfig
-	rotator *Rotator
-	client  *http.Client
+	cfg       GeminiConfig
+	selector  KeySelector
+	breaker   *CircuitBreaker
+	client    *http.Client
+	deadlines *deadlineTimer
+
+	// Logger records per-attempt provider/model/attempt/key_index
+	// observations from the retry and key-selection loops below, which the
+	// router can't see since it only observes the aggregate Complete/Stream
+	// call. Defaults to a no-op logger.
+	Logger hclog.Logger
 }
 
 // NewGeminiAdapter creates a new adapter.
@@ -40,9 +74,93 @@ func NewGeminiAdapter(cfg GeminiConfig) *GeminiAdapter {
 		cfg.Temperature = 0.3
 	}
 	return &GeminiAdapter{
-		cfg:     cfg,
-		rotator: NewRotator(cfg.APIKeys),
-		client:  &http.Client{Timeout: 60 * time.Second},
+		cfg:       cfg,
+		selector:  NewKeySelector(cfg.RotationStrategy, cfg.APIKeys),
+		breaker:   NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		client:    &http.Client{},
+		deadlines: newDeadlineTimer(),
+		Logger:    hclog.NewNullLogger(),
+	}
+}
+
+// keyIndex returns key's position in cfg.APIKeys, or -1 if it isn't one of
+// the configured keys (e.g. a KeyPool-managed key outside that list).
+func (a *GeminiAdapter) keyIndex(key string) int {
+	for i, k := range a.cfg.APIKeys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// KeySnapshot reports the rotation health of every configured API key. A
+// KeyPool-backed adapter reports its own available/cooling_down/disabled
+// state and leaves Breaker empty, since it doesn't use the CircuitBreaker;
+// every other selector gets its breaker state overlaid as before.
+func (a *GeminiAdapter) KeySnapshot() []KeyState {
+	states := a.selector.Snapshot()
+	if _, ok := a.selector.(*KeyPool); ok {
+		return states
+	}
+	for i := range states {
+		states[i].Breaker = a.breaker.State(states[i].Key).String()
+	}
+	return states
+}
+
+// SetReadDeadline sets (or, with a zero Time, clears) the deadline by
+// which an in-flight response must finish being read. Safe to call
+// mid-request; it takes effect immediately.
+func (a *GeminiAdapter) SetReadDeadline(t time.Time) {
+	a.deadlines.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets (or, with a zero Time, clears) the deadline by
+// which an in-flight request must finish being sent. Safe to call
+// mid-request; it takes effect immediately.
+func (a *GeminiAdapter) SetWriteDeadline(t time.Time) {
+	a.deadlines.SetWriteDeadline(t)
+}
+
+// doRaw performs req, racing it against the configured read/write
+// deadlines so a call in flight can be aborted by a deadline set after it
+// started, not just a fixed client-wide timeout. On success, ctx's
+// cancel is deferred to the response body being closed (via
+// netutil.CancelOnCloseBody) rather than fired the instant doRaw returns -
+// canceling it eagerly would abort the body read the moment headers
+// arrived, which is fatal for Stream, which reads resp.Body well after
+// doRaw has returned.
+func (a *GeminiAdapter) doRaw(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := a.client.Do(req)
+		done <- result{resp, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			cancel()
+			return nil, r.err
+		}
+		r.resp.Body = &netutil.CancelOnCloseBody{ReadCloser: r.resp.Body, Cancel: cancel}
+		return r.resp, nil
+	case <-a.deadlines.readDone():
+		cancel()
+		<-done
+		return nil, fmt.Errorf("read deadline exceeded")
+	case <-a.deadlines.writeDone():
+		cancel()
+		<-done
+		return nil, fmt.Errorf("write deadline exceeded")
 	}
 }
 
@@ -62,23 +180,86 @@ func (a *GeminiAdapter) Complete(ctx context.Context, req CompletionRequest) (LL
 
 	payload := buildGeminiPayload(req.Messages, req.Tools, temp)
 
-	tries := len(a.cfg.APIKeys)
-	if tries == 0 {
+	if len(a.cfg.APIKeys) == 0 {
 		return LLMResponse{}, ProviderError{Provider: "gemini", Message: "no Gemini API keys configured"}
 	}
 
+	if pool, ok := a.selector.(*KeyPool); ok {
+		return a.completeWithPool(ctx, pool, payload, model)
+	}
+
+	if a.breaker.AllOpen(a.cfg.APIKeys) {
+		return LLMResponse{}, ErrAllKeysUnhealthy{Provider: "gemini"}
+	}
+
 	var lastErr error
-	for i := 0; i < tries; i++ {
-		key, err := a.nextKey()
+	for attempt := 0; attempt < a.cfg.Retry.attempts(); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(a.cfg.Retry.Delay(attempt - 1)):
+			case <-ctx.Done():
+				return LLMResponse{}, ctx.Err()
+			}
+		}
+		key, err := a.nextHealthyKey()
 		if err != nil {
 			lastErr = err
-			continue
+			break
+		}
+		start := time.Now()
+		resp, err := a.sendRequest(ctx, payload, model, key)
+		if err == nil {
+			a.selector.MarkSuccess(key, time.Since(start))
+			a.breaker.RecordSuccess(key)
+			a.Logger.Debug("complete succeeded", "provider", "gemini", "model", model, "attempt", attempt, "key_index", a.keyIndex(key), "duration_ms", time.Since(start).Milliseconds())
+			return resp, nil
+		}
+		a.selector.MarkFailure(key, err)
+		a.breaker.RecordFailure(key)
+		lastErr = err
+		a.Logger.Warn("complete attempt failed", "provider", "gemini", "model", model, "attempt", attempt, "key_index", a.keyIndex(key), "error", err.Error(), "duration_ms", time.Since(start).Milliseconds())
+		if !a.cfg.Retry.Retryable(err) {
+			break
+		}
+	}
+	if lastErr != nil {
+		return LLMResponse{}, lastErr
+	}
+	return LLMResponse{}, ProviderError{Provider: "gemini", Message: "request failed"}
+}
+
+// completeWithPool is Complete's request loop for a KeyPool-backed adapter:
+// the pool itself decides which keys are worth trying, so there's no
+// CircuitBreaker gate here, just the existing RetryPolicy pacing retries
+// between attempts.
+func (a *GeminiAdapter) completeWithPool(ctx context.Context, pool *KeyPool, payload map[string]any, model string) (LLMResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < a.cfg.Retry.attempts(); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(a.cfg.Retry.Delay(attempt - 1)):
+			case <-ctx.Done():
+				return LLMResponse{}, ctx.Err()
+			}
+		}
+		key := pool.Next()
+		if key == "" {
+			reason, nextReady := pool.reasonForUnavailable()
+			return LLMResponse{}, PoolUnavailable{Provider: "gemini", Reason: reason, NextReadyAt: nextReady}
 		}
+		start := time.Now()
 		resp, err := a.sendRequest(ctx, payload, model, key)
 		if err == nil {
+			pool.MarkSuccess(key, time.Since(start))
+			a.Logger.Debug("complete succeeded", "provider", "gemini", "model", model, "attempt", attempt, "key_index", a.keyIndex(key), "duration_ms", time.Since(start).Milliseconds())
 			return resp, nil
 		}
+		pool.MarkFailure(key, err)
 		lastErr = err
+		a.Logger.Warn("complete attempt failed", "provider", "gemini", "model", model, "attempt", attempt, "key_index", a.keyIndex(key), "error", err.Error(), "duration_ms", time.Since(start).Milliseconds())
+		if !a.cfg.Retry.Retryable(err) {
+			break
+		}
 	}
 	if lastErr != nil {
 		return LLMResponse{}, lastErr
@@ -86,12 +267,195 @@ func (a *GeminiAdapter) Complete(ctx context.Context, req CompletionRequest) (LL
 	return LLMResponse{}, ProviderError{Provider: "gemini", Message: "request failed"}
 }
 
-func (a *GeminiAdapter) nextKey() (string, error) {
-	key := a.rotator.Next()
-	if key == "" {
-		return "", fmt.Errorf("no Gemini API keys configured")
+// nextHealthyKey returns the next key in rotation whose circuit breaker
+// currently allows an attempt, skipping any that are tripped open.
+func (a *GeminiAdapter) nextHealthyKey() (string, error) {
+	tries := len(a.cfg.APIKeys)
+	for i := 0; i < tries; i++ {
+		key := a.selector.Next()
+		if key == "" {
+			break
+		}
+		if a.breaker.Allow(key) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("no healthy Gemini API keys available")
+}
+
+// Stream calls the Gemini streaming endpoint (alt=sse) and emits one
+// LLMResponse per SSE chunk on the returned channel, which is closed when
+// the stream ends, errors, or idles for longer than StreamIdleTimeout.
+// Key selection and outcome recording mirror Complete: a KeyPool-backed
+// adapter uses the pool's own health/cooldown logic, every other selector
+// goes through the CircuitBreaker the same way Complete does.
+func (a *GeminiAdapter) Stream(ctx context.Context, req CompletionRequest) (<-chan LLMResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = a.cfg.Model
+	}
+	if !geminiModelAllowed(model) {
+		return nil, ProviderError{Provider: "gemini", Message: fmt.Sprintf("model not allowed: %s", model)}
+	}
+	temp := a.cfg.Temperature
+	if req.Temperature != nil {
+		temp = *req.Temperature
+	}
+	payload := buildGeminiPayload(req.Messages, req.Tools, temp)
+
+	pool, usingPool := a.selector.(*KeyPool)
+	var key string
+	if usingPool {
+		key = pool.Next()
+		if key == "" {
+			reason, nextReady := pool.reasonForUnavailable()
+			return nil, PoolUnavailable{Provider: "gemini", Reason: reason, NextReadyAt: nextReady}
+		}
+	} else {
+		if a.breaker.AllOpen(a.cfg.APIKeys) {
+			return nil, ErrAllKeysUnhealthy{Provider: "gemini"}
+		}
+		var err error
+		key, err = a.nextHealthyKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, ProviderError{Provider: "gemini", Message: err.Error()}
+	}
+	endpoint := strings.TrimRight(a.cfg.BaseURL, "/") + "/v1beta/models/" + model + ":streamGenerateContent?alt=sse"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, ProviderError{Provider: "gemini", Message: err.Error()}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", key)
+
+	readTimeout := a.cfg.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = defaultGeminiTimeout
+	}
+	a.SetReadDeadline(time.Now().Add(readTimeout))
+	if a.cfg.WriteTimeout > 0 {
+		a.SetWriteDeadline(time.Now().Add(a.cfg.WriteTimeout))
+	}
+
+	start := time.Now()
+	resp, err := a.doRaw(httpReq)
+	if err != nil {
+		a.markStreamFailure(pool, usingPool, key, err)
+		return nil, ProviderError{Provider: "gemini", Message: err.Error()}
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		streamErr := classifyGeminiError(resp.StatusCode, resp.Header, body)
+		a.markStreamFailure(pool, usingPool, key, streamErr)
+		return nil, streamErr
+	}
+
+	out := make(chan LLMResponse)
+	go a.readStream(ctx, resp, key, start, pool, usingPool, out)
+	return out, nil
+}
+
+// markStreamFailure and markStreamSuccess record a streaming attempt's
+// outcome the same way completeWithPool/Complete do: a KeyPool-backed
+// adapter updates the pool only, every other selector also drives the
+// CircuitBreaker so a key that trips via Stream affects Complete (and
+// vice versa).
+func (a *GeminiAdapter) markStreamFailure(pool *KeyPool, usingPool bool, key string, err error) {
+	if usingPool {
+		pool.MarkFailure(key, err)
+		return
+	}
+	a.selector.MarkFailure(key, err)
+	a.breaker.RecordFailure(key)
+}
+
+func (a *GeminiAdapter) markStreamSuccess(pool *KeyPool, usingPool bool, key string, latency time.Duration) {
+	if usingPool {
+		pool.MarkSuccess(key, latency)
+		return
+	}
+	a.selector.MarkSuccess(key, latency)
+	a.breaker.RecordSuccess(key)
+}
+
+// readStream reads SSE "data: {...}" chunks off resp.Body, decoding each as
+// a partial Gemini response and forwarding it on out. It gives up if ctx
+// is canceled or no chunk arrives within the adapter's idle timeout.
+func (a *GeminiAdapter) readStream(ctx context.Context, resp *http.Response, key string, start time.Time, pool *KeyPool, usingPool bool, out chan<- LLMResponse) {
+	defer close(out)
+	defer resp.Body.Close()
+
+	idleTimeout := a.cfg.StreamIdleTimeout
+	if idleTimeout == 0 {
+		idleTimeout = defaultStreamIdleTimeout
+	}
+
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		scanErr <- scanner.Err()
+		close(lines)
+	}()
+
+	timer := time.NewTimer(idleTimeout)
+	defer timer.Stop()
+
+	var received bool
+	for {
+		select {
+		case <-ctx.Done():
+			a.markStreamFailure(pool, usingPool, key, ctx.Err())
+			return
+		case <-timer.C:
+			a.markStreamFailure(pool, usingPool, key, fmt.Errorf("stream idle timeout exceeded"))
+			return
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-scanErr; err != nil {
+					a.markStreamFailure(pool, usingPool, key, err)
+				} else if received {
+					a.markStreamSuccess(pool, usingPool, key, time.Since(start))
+				}
+				return
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(idleTimeout)
+
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			chunk := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if chunk == "" || chunk == "[DONE]" {
+				continue
+			}
+			var raw map[string]any
+			if err := json.Unmarshal([]byte(chunk), &raw); err != nil {
+				continue
+			}
+			content, toolCalls := parseGeminiResponse(raw)
+			received = true
+			select {
+			case out <- LLMResponse{Content: content, ToolCalls: toolCalls, Raw: raw, Usage: parseGeminiUsage(raw)}:
+			case <-ctx.Done():
+				return
+			}
+		}
 	}
-	return key, nil
 }
 
 func geminiModelAllowed(model string) bool {
@@ -165,7 +529,16 @@ func (a *GeminiAdapter) sendRequest(ctx context.Context, payload map[string]any,
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-goog-api-key", apiKey)
 
-	resp, err := a.client.Do(req)
+	readTimeout := a.cfg.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = defaultGeminiTimeout
+	}
+	a.SetReadDeadline(time.Now().Add(readTimeout))
+	if a.cfg.WriteTimeout > 0 {
+		a.SetWriteDeadline(time.Now().Add(a.cfg.WriteTimeout))
+	}
+
+	resp, err := a.doRaw(req)
 	if err != nil {
 		return LLMResponse{}, ProviderError{Provider: "gemini", Message: err.Error()}
 	}
@@ -173,21 +546,7 @@ func (a *GeminiAdapter) sendRequest(ctx context.Context, payload map[string]any,
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 400 {
-		msg := strings.TrimSpace(string(body))
-		lowered := strings.ToLower(msg)
-		switch resp.StatusCode {
-		case http.StatusUnauthorized, http.StatusForbidden:
-			return LLMResponse{}, ProviderError{Provider: "gemini", Message: "auth error: " + msg}
-		case http.StatusTooManyRequests:
-			return LLMResponse{}, ProviderError{Provider: "gemini", Message: "rate limit: " + msg}
-		}
-		if strings.Contains(lowered, "quota") || strings.Contains(lowered, "resource_exhausted") {
-			return LLMResponse{}, ProviderError{Provider: "gemini", Message: "rate limit: " + msg}
-		}
-		if resp.StatusCode >= 500 {
-			return LLMResponse{}, ProviderError{Provider: "gemini", Message: "server error: " + msg}
-		}
-		return LLMResponse{}, ProviderError{Provider: "gemini", Message: "api error: " + msg}
+		return LLMResponse{}, classifyGeminiError(resp.StatusCode, resp.Header, body)
 	}
 
 	var raw map[string]any
@@ -195,7 +554,75 @@ func (a *GeminiAdapter) sendRequest(ctx context.Context, payload map[string]any,
 		return LLMResponse{}, ProviderError{Provider: "gemini", Message: "invalid json response"}
 	}
 	content, toolCalls := parseGeminiResponse(raw)
-	return LLMResponse{Content: content, ToolCalls: toolCalls, Raw: raw}, nil
+	return LLMResponse{Content: content, ToolCalls: toolCalls, Raw: raw, Usage: parseGeminiUsage(raw)}, nil
+}
+
+// classifyGeminiError turns a >=400 Gemini response into a ProviderError
+// with the Kind/RetryAfter the key rotation and retry logic need to react
+// correctly to auth, quota, and server errors, shared by sendRequest's
+// non-streaming path and Stream's SSE handshake so both drive
+// KeyPool/CircuitBreaker the same way on the same error classes.
+func classifyGeminiError(statusCode int, header http.Header, body []byte) ProviderError {
+	msg := strings.TrimSpace(string(body))
+	lowered := strings.ToLower(msg)
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ProviderError{Provider: "gemini", Kind: ErrKindAuth, Message: "auth error: " + msg}
+	case http.StatusTooManyRequests:
+		return ProviderError{Provider: "gemini", Kind: ErrKindQuota, RetryAfter: parseGeminiRetryAfter(header, body), Message: "rate limit: " + msg}
+	}
+	if strings.Contains(lowered, "quota") || strings.Contains(lowered, "resource_exhausted") {
+		return ProviderError{Provider: "gemini", Kind: ErrKindQuota, RetryAfter: parseGeminiRetryAfter(header, body), Message: "rate limit: " + msg}
+	}
+	if statusCode >= 500 {
+		return ProviderError{Provider: "gemini", Kind: ErrKindServer, Message: "server error: " + msg}
+	}
+	return ProviderError{Provider: "gemini", Message: "api error: " + msg}
+}
+
+// parseGeminiRetryAfter extracts how long Gemini asked the caller to wait
+// before retrying, preferring the standard Retry-After header and falling
+// back to the retryDelay field of an embedded google.rpc.RetryInfo detail
+// (e.g. {"error":{"details":[{"@type":".../RetryInfo","retryDelay":"30s"}]}}).
+// It returns 0 if neither is present or parseable.
+func parseGeminiRetryAfter(header http.Header, body []byte) time.Duration {
+	if raw := header.Get("Retry-After"); raw != "" {
+		if secs, err := strconv.Atoi(raw); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	var parsed struct {
+		Error struct {
+			Details []struct {
+				Type       string `json:"@type"`
+				RetryDelay string `json:"retryDelay"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0
+	}
+	for _, d := range parsed.Error.Details {
+		if !strings.Contains(d.Type, "RetryInfo") || d.RetryDelay == "" {
+			continue
+		}
+		if dur, err := time.ParseDuration(d.RetryDelay); err == nil {
+			return dur
+		}
+	}
+	return 0
+}
+
+// parseGeminiUsage extracts prompt/completion token counts from Gemini's
+// usageMetadata block, when the response includes one.
+func parseGeminiUsage(raw map[string]any) Usage {
+	meta, ok := raw["usageMetadata"].(map[string]any)
+	if !ok {
+		return Usage{}
+	}
+	prompt, _ := meta["promptTokenCount"].(float64)
+	completion, _ := meta["candidatesTokenCount"].(float64)
+	return Usage{PromptTokens: int(prompt), CompletionTokens: int(completion)}
 }
 
 func parseGeminiResponse(raw map[string]any) (string, []ToolCall) {
@@ -0,0 +1,236 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// keyStatus is a key's membership in the pool: available to try, cooling
+// down until a quota resets, or permanently disabled after an auth failure.
+type keyStatus int
+
+const (
+	keyAvailable keyStatus = iota
+	keyCoolingDown
+	keyDisabled
+)
+
+const defaultCooldownBase = 5 * time.Second
+const defaultCooldownMax = 5 * time.Minute
+
+type poolEntry struct {
+	status       keyStatus
+	coolingUntil time.Time
+	failures     int
+	health       float64
+	lastUsed     time.Time
+}
+
+// KeyPool is a KeySelector that tracks richer per-key health than
+// CircuitBreaker: a key is available, cooling down until a deadline (quota
+// or rate limit), or disabled outright (auth failure), and available keys
+// are ranked by a health score that 5xx responses erode rather than
+// tripping the key out of rotation entirely.
+type KeyPool struct {
+	mu      sync.Mutex
+	keys    []string
+	entries map[string]*poolEntry
+}
+
+// NewKeyPool creates a KeyPool over keys, all initially available.
+func NewKeyPool(keys []string) *KeyPool {
+	entries := make(map[string]*poolEntry, len(keys))
+	for _, k := range keys {
+		entries[k] = &poolEntry{status: keyAvailable, health: 1}
+	}
+	return &KeyPool{keys: keys, entries: entries}
+}
+
+// Next returns the highest-scored available key, promoting any cooling-down
+// key whose deadline has passed back to available first. It returns "" if
+// every key is disabled or still cooling down.
+func (p *KeyPool) Next() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+
+	var best string
+	bestScore := -1.0
+	for _, k := range p.keys {
+		e := p.entries[k]
+		if e.status == keyCoolingDown && !now.Before(e.coolingUntil) {
+			e.status = keyAvailable
+		}
+		if e.status != keyAvailable {
+			continue
+		}
+		if e.health > bestScore {
+			best, bestScore = k, e.health
+		}
+	}
+	if best != "" {
+		p.entries[best].lastUsed = now
+	}
+	return best
+}
+
+// MarkFailure updates key's state based on err's ProviderError classification:
+// an auth error disables the key outright, a quota error cools it down using
+// an exponential backoff seeded from the error's RetryAfter hint if one was
+// provided, and anything else just erodes the key's health score while
+// leaving it available.
+func (p *KeyPool) MarkFailure(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.entries[key]
+	if !ok {
+		return
+	}
+	e.failures++
+
+	var perr ProviderError
+	if errors.As(err, &perr) {
+		switch perr.Kind {
+		case ErrKindAuth:
+			e.status = keyDisabled
+			return
+		case ErrKindQuota:
+			delay := perr.RetryAfter
+			if delay <= 0 {
+				delay = cooldownBackoff(e.failures)
+			}
+			e.status = keyCoolingDown
+			e.coolingUntil = time.Now().Add(delay)
+			return
+		}
+	}
+
+	e.health *= 0.5
+	if e.health < 0.05 {
+		e.health = 0.05
+	}
+}
+
+// MarkSuccess clears key's failure count and restores it to full health.
+func (p *KeyPool) MarkSuccess(key string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.entries[key]
+	if !ok {
+		return
+	}
+	e.failures = 0
+	e.status = keyAvailable
+	e.health = 1
+}
+
+// Snapshot reports every key's pool state. QuotaRemaining carries the key's
+// health score (1 is full health, eroded by 5xx responses) since the pool
+// doesn't track provider-reported quota directly.
+func (p *KeyPool) Snapshot() []KeyState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]KeyState, 0, len(p.keys))
+	for _, k := range p.keys {
+		e := p.entries[k]
+		out = append(out, KeyState{
+			Key:            k,
+			Healthy:        e.status != keyDisabled,
+			LastUsed:       e.lastUsed,
+			Failures:       e.failures,
+			CooldownUntil:  e.coolingUntil,
+			QuotaRemaining: e.health,
+		})
+	}
+	return out
+}
+
+// reasonForUnavailable explains why Next returned "", for a caller building
+// a PoolUnavailable error: if any key is cooling down, that's the reason
+// (with the earliest deadline among them), otherwise it's because every key
+// is disabled, otherwise the pool itself is empty.
+func (p *KeyPool) reasonForUnavailable() (PoolUnavailableReason, time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var nextReady time.Time
+	anyDisabled := false
+	anyCooling := false
+	for _, k := range p.keys {
+		e := p.entries[k]
+		switch e.status {
+		case keyDisabled:
+			anyDisabled = true
+		case keyCoolingDown:
+			anyCooling = true
+			if nextReady.IsZero() || e.coolingUntil.Before(nextReady) {
+				nextReady = e.coolingUntil
+			}
+		}
+	}
+	switch {
+	case anyCooling:
+		return AllKeysCoolingDown, nextReady
+	case anyDisabled:
+		return AuthExhausted, time.Time{}
+	default:
+		return Transient, time.Time{}
+	}
+}
+
+// cooldownBackoff returns an exponential cooldown for a key's nth
+// consecutive quota failure, capped at defaultCooldownMax.
+func cooldownBackoff(failures int) time.Duration {
+	delay := defaultCooldownBase * time.Duration(uint64(1)<<uint(failures-1))
+	if delay <= 0 || delay > defaultCooldownMax {
+		delay = defaultCooldownMax
+	}
+	return delay
+}
+
+// PoolUnavailableReason explains why a KeyPool had no key to offer.
+type PoolUnavailableReason int
+
+const (
+	// AuthExhausted means every key has been disabled by an auth failure.
+	AuthExhausted PoolUnavailableReason = iota
+	// AllKeysCoolingDown means every key is rate-limited or over quota;
+	// NextReadyAt on the accompanying PoolUnavailable reports when the
+	// earliest one becomes available again.
+	AllKeysCoolingDown
+	// Transient covers anything else, including an empty pool.
+	Transient
+)
+
+// String returns the lowercase, metrics/JSON-friendly name of the reason.
+func (r PoolUnavailableReason) String() string {
+	switch r {
+	case AuthExhausted:
+		return "auth_exhausted"
+	case AllKeysCoolingDown:
+		return "all_keys_cooling_down"
+	default:
+		return "transient"
+	}
+}
+
+// PoolUnavailable is returned by GeminiAdapter.Complete when its KeyPool has
+// no key to try, distinguishing why so callers can react appropriately
+// (e.g. surface a 503 with a Retry-After derived from NextReadyAt).
+type PoolUnavailable struct {
+	Provider    string
+	Reason      PoolUnavailableReason
+	NextReadyAt time.Time
+}
+
+func (e PoolUnavailable) Error() string {
+	switch e.Reason {
+	case AuthExhausted:
+		return fmt.Sprintf("%s: all API keys exhausted (auth)", e.Provider)
+	case AllKeysCoolingDown:
+		return fmt.Sprintf("%s: all API keys cooling down until %s", e.Provider, e.NextReadyAt.Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("%s: no API key available", e.Provider)
+	}
+}
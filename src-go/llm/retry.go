@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how a provider adapter retries a failed completion
+// against a different API key: up to MaxAttempts tries total, waiting a
+// capped exponential backoff (plus jitter) between each.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      time.Duration
+}
+
+// DefaultRetryPolicy is used wherever a zero-value RetryPolicy is
+// configured.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      200 * time.Millisecond,
+}
+
+// attempts returns the policy's MaxAttempts, falling back to
+// DefaultRetryPolicy's when unset.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryPolicy.MaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// Delay returns the backoff to wait before the given retry attempt
+// (0-indexed: 0 is the delay before the second try), computed as
+// min(base*2^attempt, max) plus a random jitter in [0, Jitter).
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	delay := base * time.Duration(uint64(1)<<uint(attempt))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	return delay
+}
+
+// Retryable reports whether err is worth retrying against a different key.
+// Rate limits, server errors, and timeouts are; auth failures and other
+// client errors aren't, since a different key won't fix those.
+func (p RetryPolicy) Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "rate limit"):
+		return true
+	case strings.Contains(msg, "server error"):
+		return true
+	case strings.Contains(msg, "timeout"):
+		return true
+	case strings.Contains(msg, "deadline exceeded"):
+		return true
+	default:
+		return false
+	}
+}
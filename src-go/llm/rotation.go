@@ -1,8 +1,58 @@
 package llm
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
-// Rotator provides round-robin selection of keys.
+// KeyState is a point-in-time snapshot of one API key's rotation health,
+// surfaced to operators via the /keys admin endpoint.
+type KeyState struct {
+	Key            string
+	Healthy        bool
+	LastUsed       time.Time
+	Failures       int
+	CooldownUntil  time.Time
+	QuotaRemaining float64
+	// Breaker is the circuit breaker state ("closed", "open", or
+	// "half_open") for adapters that track one; empty otherwise.
+	Breaker string
+}
+
+// KeySelector chooses which API key a provider adapter should use next.
+// Complete calls MarkFailure/MarkSuccess after every attempt so selectors
+// that track health or quota can adapt; selectors that don't care about
+// either (like RoundRobin) simply ignore them.
+type KeySelector interface {
+	Next() string
+	MarkFailure(key string, err error)
+	MarkSuccess(key string, latency time.Duration)
+	Snapshot() []KeyState
+}
+
+// NewKeySelector builds the KeySelector named by strategy, defaulting to
+// round-robin (the long-standing Rotator behavior) for an unrecognized or
+// empty strategy name.
+func NewKeySelector(strategy string, keys []string) KeySelector {
+	switch strategy {
+	case "random":
+		return NewRandomSelector(keys)
+	case "least_recently_used", "lru":
+		return NewLRUSelector(keys)
+	case "weighted_by_quota", "weighted_by_quota_remaining":
+		return NewWeightedByQuotaSelector(keys)
+	case "health_aware":
+		return NewHealthAwareSelector(keys)
+	case "pool":
+		return NewKeyPool(keys)
+	default:
+		return NewRotator(keys)
+	}
+}
+
+// Rotator provides round-robin selection of keys. It is the default
+// KeySelector and the original implementation kept for backwards
+// compatibility: its Next() signature and behavior are unchanged.
 type Rotator struct {
 	mu   sync.Mutex
 	keys []string
@@ -25,3 +75,20 @@ func (r *Rotator) Next() string {
 	r.next++
 	return key
 }
+
+// MarkFailure is a no-op: round-robin rotation doesn't track key health.
+func (r *Rotator) MarkFailure(key string, err error) {}
+
+// MarkSuccess is a no-op: round-robin rotation doesn't track key health.
+func (r *Rotator) MarkSuccess(key string, latency time.Duration) {}
+
+// Snapshot reports every key as healthy, since Rotator never disables one.
+func (r *Rotator) Snapshot() []KeyState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]KeyState, 0, len(r.keys))
+	for _, k := range r.keys {
+		out = append(out, KeyState{Key: k, Healthy: true})
+	}
+	return out
+}
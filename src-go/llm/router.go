@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/metrics"
 )
 
 // LLMRouter dispatches requests to providers.
@@ -11,6 +16,14 @@ type LLMRouter struct {
 	mu              sync.RWMutex
 	defaultProvider string
 	providers       map[string]ProviderAdapter
+
+	// Metrics records request/latency/token observations for every
+	// Complete call. Defaults to a no-op recorder.
+	Metrics metrics.Recorder
+
+	// Logger records per-request provider/model/duration_ms observations.
+	// Defaults to a no-op logger.
+	Logger hclog.Logger
 }
 
 // NewRouter creates a router with a default provider.
@@ -18,6 +31,8 @@ func NewRouter(defaultProvider string) *LLMRouter {
 	return &LLMRouter{
 		defaultProvider: defaultProvider,
 		providers:       map[string]ProviderAdapter{},
+		Metrics:         metrics.NewNop(),
+		Logger:          hclog.NewNullLogger(),
 	}
 }
 
@@ -31,6 +46,116 @@ func (r *LLMRouter) RegisterProvider(name string, adapter ProviderAdapter) {
 // Complete calls the selected provider.
 func (r *LLMRouter) Complete(ctx context.Context, req CompletionRequest) (LLMResponse, error) {
 	provider := req.Provider
+	if provider == "" {
+		provider = r.defaultProvider
+	}
+	start := time.Now()
+
+	adapter, err := r.resolve(req.Provider)
+	if err != nil {
+		r.Metrics.ObserveLLMRequest(provider, req.Model, "error", time.Since(start))
+		r.Logger.Error("complete failed", "provider", provider, "model", req.Model, "error", err.Error(), "duration_ms", time.Since(start).Milliseconds())
+		return LLMResponse{}, err
+	}
+	resp, err := adapter.Complete(ctx, req)
+	r.recordKeyBreakerMetrics(provider, adapter)
+	if err != nil {
+		r.Metrics.ObserveLLMRequest(provider, req.Model, "error", time.Since(start))
+		r.Logger.Error("complete failed", "provider", provider, "model", req.Model, "error", err.Error(), "duration_ms", time.Since(start).Milliseconds())
+		return resp, err
+	}
+	r.Metrics.ObserveLLMRequest(provider, req.Model, "ok", time.Since(start))
+	r.Metrics.ObserveLLMTokens(provider, req.Model, "prompt", resp.Usage.PromptTokens)
+	r.Metrics.ObserveLLMTokens(provider, req.Model, "completion", resp.Usage.CompletionTokens)
+	r.Logger.Debug("complete succeeded", "provider", provider, "model", req.Model, "duration_ms", time.Since(start).Milliseconds())
+	return resp, nil
+}
+
+// recordKeyBreakerMetrics reports adapter's current per-key circuit
+// breaker state to Metrics, for adapters that track one.
+func (r *LLMRouter) recordKeyBreakerMetrics(provider string, adapter ProviderAdapter) {
+	inspector, ok := adapter.(KeyInspector)
+	if !ok {
+		return
+	}
+	for i, state := range inspector.KeySnapshot() {
+		r.Metrics.ObserveKeyBreakerState(provider, i, state.Breaker)
+	}
+}
+
+// ErrStreamingUnsupported is returned when the resolved provider has no
+// streaming implementation, so callers can fall back to Complete.
+var ErrStreamingUnsupported = fmt.Errorf("provider does not support streaming")
+
+// Stream calls the selected provider's streaming API, if it has one. The
+// returned channel is closed when the response is complete.
+func (r *LLMRouter) Stream(ctx context.Context, req CompletionRequest) (<-chan LLMResponse, error) {
+	adapter, err := r.resolve(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	streaming, ok := adapter.(StreamingAdapter)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+	return streaming.Stream(ctx, req)
+}
+
+// KeyInspector is implemented by adapters that can report the rotation
+// health of their configured API keys.
+type KeyInspector interface {
+	KeySnapshot() []KeyState
+}
+
+// KeySnapshot reports every registered provider's key rotation state, for
+// providers that track one. It backs the /keys admin endpoint.
+func (r *LLMRouter) KeySnapshot() map[string][]KeyState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := map[string][]KeyState{}
+	for name, adapter := range r.providers {
+		if inspector, ok := adapter.(KeyInspector); ok {
+			out[name] = inspector.KeySnapshot()
+		}
+	}
+	return out
+}
+
+// ProviderStatus summarizes one registered provider's key rotation and
+// circuit breaker health, for the /v1/providers admin route.
+type ProviderStatus struct {
+	Keys    []KeyState
+	Healthy bool
+}
+
+// Providers reports every registered provider's ProviderStatus. A
+// provider with no key inspector (an unconfigured or placeholder adapter)
+// is reported with no keys and Healthy true, since it has nothing to trip
+// open.
+func (r *LLMRouter) Providers() map[string]ProviderStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := map[string]ProviderStatus{}
+	for name, adapter := range r.providers {
+		inspector, ok := adapter.(KeyInspector)
+		if !ok {
+			out[name] = ProviderStatus{Healthy: true}
+			continue
+		}
+		keys := inspector.KeySnapshot()
+		healthy := len(keys) == 0
+		for _, k := range keys {
+			if k.Breaker != BreakerOpen.String() {
+				healthy = true
+				break
+			}
+		}
+		out[name] = ProviderStatus{Keys: keys, Healthy: healthy}
+	}
+	return out
+}
+
+func (r *LLMRouter) resolve(provider string) (ProviderAdapter, error) {
 	if provider == "" {
 		provider = r.defaultProvider
 	}
@@ -38,7 +163,7 @@ func (r *LLMRouter) Complete(ctx context.Context, req CompletionRequest) (LLMRes
 	adapter, ok := r.providers[provider]
 	r.mu.RUnlock()
 	if !ok {
-		return LLMResponse{}, fmt.Errorf("provider not registered: %s", provider)
+		return nil, fmt.Errorf("provider not registered: %s", provider)
 	}
-	return adapter.Complete(ctx, req)
+	return adapter, nil
 }
@@ -0,0 +1,250 @@
+package llm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// keyHealthCooldown is how long a key that just failed is skipped by
+// HealthAwareSelector before being tried again.
+const keyHealthCooldown = 30 * time.Second
+
+// RandomSelector picks a uniformly random key on every call.
+type RandomSelector struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+// NewRandomSelector creates a RandomSelector over keys.
+func NewRandomSelector(keys []string) *RandomSelector {
+	return &RandomSelector{keys: keys}
+}
+
+func (s *RandomSelector) Next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) == 0 {
+		return ""
+	}
+	return s.keys[rand.Intn(len(s.keys))]
+}
+
+func (s *RandomSelector) MarkFailure(key string, err error)             {}
+func (s *RandomSelector) MarkSuccess(key string, latency time.Duration) {}
+
+func (s *RandomSelector) Snapshot() []KeyState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]KeyState, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, KeyState{Key: k, Healthy: true})
+	}
+	return out
+}
+
+// LRUSelector always picks the key that was used longest ago (or never
+// used), which spreads load evenly across keys that succeed at different
+// rates.
+type LRUSelector struct {
+	mu       sync.Mutex
+	keys     []string
+	lastUsed map[string]time.Time
+}
+
+// NewLRUSelector creates an LRUSelector over keys.
+func NewLRUSelector(keys []string) *LRUSelector {
+	return &LRUSelector{keys: keys, lastUsed: map[string]time.Time{}}
+}
+
+func (s *LRUSelector) Next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) == 0 {
+		return ""
+	}
+	oldest := s.keys[0]
+	oldestTime := s.lastUsed[oldest]
+	for _, k := range s.keys[1:] {
+		if t := s.lastUsed[k]; t.Before(oldestTime) {
+			oldest, oldestTime = k, t
+		}
+	}
+	s.lastUsed[oldest] = time.Now()
+	return oldest
+}
+
+func (s *LRUSelector) MarkFailure(key string, err error)             {}
+func (s *LRUSelector) MarkSuccess(key string, latency time.Duration) {}
+
+func (s *LRUSelector) Snapshot() []KeyState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]KeyState, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, KeyState{Key: k, Healthy: true, LastUsed: s.lastUsed[k]})
+	}
+	return out
+}
+
+// quotaRecoveryPerSecond is how fast a key's quota drifts back toward 1.0
+// while idle - full recovery from empty takes about 100s.
+const quotaRecoveryPerSecond = 0.01
+
+// WeightedByQuotaSelector favors the key with the most quota remaining.
+// Quota starts at 1.0 per key and is nudged down on every success (a
+// stand-in for a real provider-reported remaining-quota value) and back
+// up slowly over time so a key recovers after a quiet period.
+type WeightedByQuotaSelector struct {
+	mu      sync.Mutex
+	keys    []string
+	quota   map[string]float64
+	updated map[string]time.Time
+}
+
+// NewWeightedByQuotaSelector creates a WeightedByQuotaSelector over keys.
+func NewWeightedByQuotaSelector(keys []string) *WeightedByQuotaSelector {
+	quota := make(map[string]float64, len(keys))
+	updated := make(map[string]time.Time, len(keys))
+	now := time.Now()
+	for _, k := range keys {
+		quota[k] = 1.0
+		updated[k] = now
+	}
+	return &WeightedByQuotaSelector{keys: keys, quota: quota, updated: updated}
+}
+
+// recoverLocked drifts key's quota back toward 1.0 based on how long it's
+// been since the last access, then stamps updated so the next call measures
+// from here. Must be called with s.mu held.
+func (s *WeightedByQuotaSelector) recoverLocked(key string) {
+	now := time.Now()
+	if elapsed := now.Sub(s.updated[key]).Seconds(); elapsed > 0 {
+		s.quota[key] += elapsed * quotaRecoveryPerSecond
+		if s.quota[key] > 1.0 {
+			s.quota[key] = 1.0
+		}
+	}
+	s.updated[key] = now
+}
+
+func (s *WeightedByQuotaSelector) Next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) == 0 {
+		return ""
+	}
+	for _, k := range s.keys {
+		s.recoverLocked(k)
+	}
+	best := s.keys[0]
+	for _, k := range s.keys[1:] {
+		if s.quota[k] > s.quota[best] {
+			best = k
+		}
+	}
+	return best
+}
+
+func (s *WeightedByQuotaSelector) MarkFailure(key string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recoverLocked(key)
+	s.quota[key] -= 0.1
+	if s.quota[key] < 0 {
+		s.quota[key] = 0
+	}
+}
+
+func (s *WeightedByQuotaSelector) MarkSuccess(key string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recoverLocked(key)
+	s.quota[key] -= 0.05
+	if s.quota[key] < 0 {
+		s.quota[key] = 0
+	}
+}
+
+func (s *WeightedByQuotaSelector) Snapshot() []KeyState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]KeyState, 0, len(s.keys))
+	for _, k := range s.keys {
+		s.recoverLocked(k)
+		out = append(out, KeyState{Key: k, Healthy: s.quota[k] > 0, QuotaRemaining: s.quota[k]})
+	}
+	return out
+}
+
+// HealthAwareSelector round-robins over keys but skips any key that
+// returned an error within the last keyHealthCooldown, falling back to
+// the least-recently-failed key if every key is currently cooling down.
+type HealthAwareSelector struct {
+	mu         sync.Mutex
+	keys       []string
+	next       int
+	failures   map[string]int
+	cooldownTo map[string]time.Time
+}
+
+// NewHealthAwareSelector creates a HealthAwareSelector over keys.
+func NewHealthAwareSelector(keys []string) *HealthAwareSelector {
+	return &HealthAwareSelector{
+		keys:       keys,
+		failures:   map[string]int{},
+		cooldownTo: map[string]time.Time{},
+	}
+}
+
+func (s *HealthAwareSelector) Next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) == 0 {
+		return ""
+	}
+	now := time.Now()
+	for i := 0; i < len(s.keys); i++ {
+		idx := (s.next + i) % len(s.keys)
+		key := s.keys[idx]
+		if now.After(s.cooldownTo[key]) {
+			s.next = idx + 1
+			return key
+		}
+	}
+	// Every key is cooling down; fall back to plain round-robin so we
+	// still make forward progress rather than refusing to pick a key.
+	key := s.keys[s.next%len(s.keys)]
+	s.next++
+	return key
+}
+
+func (s *HealthAwareSelector) MarkFailure(key string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[key]++
+	s.cooldownTo[key] = time.Now().Add(keyHealthCooldown)
+}
+
+func (s *HealthAwareSelector) MarkSuccess(key string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[key] = 0
+	delete(s.cooldownTo, key)
+}
+
+func (s *HealthAwareSelector) Snapshot() []KeyState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	out := make([]KeyState, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, KeyState{
+			Key:           k,
+			Healthy:       now.After(s.cooldownTo[k]),
+			Failures:      s.failures[k],
+			CooldownUntil: s.cooldownTo[k],
+		})
+	}
+	return out
+}
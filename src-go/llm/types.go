@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tunapro1234/base-agent/src-go/tools"
 )
@@ -19,11 +20,19 @@ type ToolCall struct {
 	Args map[string]any
 }
 
+// Usage reports token accounting for a single completion, when the
+// provider includes it in its response.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
 // LLMResponse is the model response.
 type LLMResponse struct {
 	Content   string
 	ToolCalls []ToolCall
 	Raw       map[string]any
+	Usage     Usage
 }
 
 // CompletionRequest is the standard request to a provider.
@@ -41,10 +50,38 @@ type ProviderAdapter interface {
 	Complete(ctx context.Context, req CompletionRequest) (LLMResponse, error)
 }
 
+// StreamingAdapter is implemented by providers that can emit incremental
+// responses. Adapters that only support Complete fall back to the
+// buffered path transparently.
+type StreamingAdapter interface {
+	ProviderAdapter
+	Stream(ctx context.Context, req CompletionRequest) (<-chan LLMResponse, error)
+}
+
+// ProviderErrorKind classifies why a provider call failed, so a KeyPool can
+// decide whether to disable the key, put it in cooldown, or just penalize
+// its health score. The zero value, ErrKindUnspecified, means the caller
+// couldn't tell and should treat the failure as transient.
+type ProviderErrorKind int
+
+const (
+	ErrKindUnspecified ProviderErrorKind = iota
+	ErrKindAuth
+	ErrKindQuota
+	ErrKindServer
+)
+
 // ProviderError wraps provider failures.
 type ProviderError struct {
 	Provider string
 	Message  string
+	// Kind classifies the failure for callers like KeyPool. Optional:
+	// adapters that don't classify their errors leave it unspecified.
+	Kind ProviderErrorKind
+	// RetryAfter is how long the provider asked the caller to wait before
+	// retrying, parsed from a Retry-After header or similar provider-native
+	// hint. Zero if the provider didn't specify one.
+	RetryAfter time.Duration
 }
 
 func (e ProviderError) Error() string {
@@ -0,0 +1,185 @@
+// Package metrics exposes the Recorder interface that collectors across the
+// module (router, tools, tasks, HTTP API) report through, plus a
+// Prometheus-backed implementation. Callers that don't want metrics wired
+// up just use NewNop.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Recorder is implemented by anything that wants to observe the module's
+// request/tool/task lifecycle. NewNop satisfies it as a no-op for tests
+// and callers that don't want metrics wired up.
+type Recorder interface {
+	ObserveHTTPRequest(path, method, status string, duration time.Duration)
+	ObserveLLMRequest(provider, model, status string, duration time.Duration)
+	ObserveLLMTokens(provider, model, kind string, count int)
+	ObserveToolExecution(name string, success bool, duration time.Duration)
+	ObserveTaskCreated()
+	ObserveTaskStatus(status string)
+	ObserveKeyBreakerState(provider string, keyIndex int, state string)
+}
+
+// NewNop returns a Recorder that discards every observation.
+func NewNop() Recorder { return nopRecorder{} }
+
+type nopRecorder struct{}
+
+func (nopRecorder) ObserveHTTPRequest(string, string, string, time.Duration) {}
+func (nopRecorder) ObserveLLMRequest(string, string, string, time.Duration)  {}
+func (nopRecorder) ObserveLLMTokens(string, string, string, int)             {}
+func (nopRecorder) ObserveToolExecution(string, bool, time.Duration)         {}
+func (nopRecorder) ObserveTaskCreated()                                      {}
+func (nopRecorder) ObserveTaskStatus(string)                                 {}
+func (nopRecorder) ObserveKeyBreakerState(string, int, string)               {}
+
+// PromRecorder is the Recorder backed by a real prometheus.Registry,
+// served at /metrics via Handler.
+type PromRecorder struct {
+	Registry *prometheus.Registry
+
+	httpRequestsTotal     *prometheus.CounterVec
+	httpRequestDuration   *prometheus.HistogramVec
+	llmRequestsTotal      *prometheus.CounterVec
+	llmRequestDuration    *prometheus.HistogramVec
+	llmTokensTotal        *prometheus.CounterVec
+	toolExecutionsTotal   *prometheus.CounterVec
+	toolExecutionDuration *prometheus.HistogramVec
+	tasksTotal            *prometheus.CounterVec
+	tasksInFlight         prometheus.Gauge
+	llmKeyBreakerState    *prometheus.GaugeVec
+}
+
+// NewPromRecorder builds a PromRecorder with a fresh registry and
+// registers all of its collectors.
+func NewPromRecorder() *PromRecorder {
+	r := &PromRecorder{
+		Registry: prometheus.NewRegistry(),
+		httpRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_server_requests_total",
+			Help: "Total HTTP requests served by the agent API.",
+		}, []string{"path", "method", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "http_server_request_duration_seconds",
+			Help: "HTTP request latency in seconds.",
+		}, []string{"path", "method"}),
+		llmRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "llm_requests_total",
+			Help: "Total completion requests issued to LLM providers.",
+		}, []string{"provider", "model", "status"}),
+		llmRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "llm_request_duration_seconds",
+			Help: "LLM completion request latency in seconds.",
+		}, []string{"provider", "model"}),
+		llmTokensTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "llm_tokens_total",
+			Help: "Total tokens reported by LLM providers, by kind (prompt, completion).",
+		}, []string{"provider", "model", "kind"}),
+		toolExecutionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tool_executions_total",
+			Help: "Total tool invocations.",
+		}, []string{"name", "success"}),
+		toolExecutionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "tool_execution_duration_seconds",
+			Help: "Tool execution latency in seconds.",
+		}, []string{"name"}),
+		tasksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tasks_total",
+			Help: "Total tasks created, by terminal status.",
+		}, []string{"status"}),
+		tasksInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tasks_in_flight",
+			Help: "Tasks currently pending or running.",
+		}),
+		llmKeyBreakerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "llm_key_breaker_state",
+			Help: "Circuit breaker state per API key: 0=closed, 0.5=half_open, 1=open.",
+		}, []string{"provider", "key_index"}),
+	}
+	r.Registry.MustRegister(
+		r.httpRequestsTotal,
+		r.httpRequestDuration,
+		r.llmRequestsTotal,
+		r.llmRequestDuration,
+		r.llmTokensTotal,
+		r.toolExecutionsTotal,
+		r.toolExecutionDuration,
+		r.tasksTotal,
+		r.tasksInFlight,
+		r.llmKeyBreakerState,
+	)
+	return r
+}
+
+func (r *PromRecorder) ObserveHTTPRequest(path, method, status string, duration time.Duration) {
+	r.httpRequestsTotal.WithLabelValues(path, method, status).Inc()
+	r.httpRequestDuration.WithLabelValues(path, method).Observe(duration.Seconds())
+}
+
+func (r *PromRecorder) ObserveLLMRequest(provider, model, status string, duration time.Duration) {
+	r.llmRequestsTotal.WithLabelValues(provider, model, status).Inc()
+	r.llmRequestDuration.WithLabelValues(provider, model).Observe(duration.Seconds())
+}
+
+func (r *PromRecorder) ObserveLLMTokens(provider, model, kind string, count int) {
+	if count <= 0 {
+		return
+	}
+	r.llmTokensTotal.WithLabelValues(provider, model, kind).Add(float64(count))
+}
+
+func (r *PromRecorder) ObserveToolExecution(name string, success bool, duration time.Duration) {
+	r.toolExecutionsTotal.WithLabelValues(name, successLabel(success)).Inc()
+	r.toolExecutionDuration.WithLabelValues(name).Observe(duration.Seconds())
+}
+
+func (r *PromRecorder) ObserveTaskCreated() {
+	r.tasksInFlight.Inc()
+}
+
+func (r *PromRecorder) ObserveTaskStatus(status string) {
+	r.tasksTotal.WithLabelValues(status).Inc()
+	if status == "completed" || status == "failed" {
+		r.tasksInFlight.Dec()
+	}
+}
+
+func (r *PromRecorder) ObserveKeyBreakerState(provider string, keyIndex int, state string) {
+	value := 0.0
+	switch state {
+	case "open":
+		value = 1
+	case "half_open":
+		value = 0.5
+	}
+	r.llmKeyBreakerState.WithLabelValues(provider, strconv.Itoa(keyIndex)).Set(value)
+}
+
+func successLabel(success bool) string {
+	if success {
+		return "true"
+	}
+	return "false"
+}
+
+// Handler returns the /metrics HTTP handler, requiring a matching bearer
+// token on every request when token is non-empty.
+func (r *PromRecorder) Handler(token string) http.Handler {
+	base := promhttp.HandlerFor(r.Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return base
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		base.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,25 @@
+// Package netutil holds small HTTP client helpers shared by the server's
+// provider adapters (src-go/llm) and the debug CLI's API client
+// (src-go/debug_cli/internal/client), which otherwise have no common
+// dependency to hang them on.
+package netutil
+
+import (
+	"context"
+	"io"
+)
+
+// CancelOnCloseBody ties a request's context lifetime to its response
+// body: a deadline race that started the request can still cancel the
+// body read mid-stream, but a normal Close (the common case once the
+// body is fully consumed) releases the context instead of yanking it out
+// from under a caller still reading.
+type CancelOnCloseBody struct {
+	io.ReadCloser
+	Cancel context.CancelFunc
+}
+
+func (b *CancelOnCloseBody) Close() error {
+	defer b.Cancel()
+	return b.ReadCloser.Close()
+}
@@ -6,4 +6,33 @@ type AgentResult struct {
 	Output  string
 	TaskID  string
 	Trace   map[string]any
+	// Err is the underlying error when Success is false and the loop
+	// failed outright (as opposed to exhausting its max iterations), so
+	// callers can type-switch on it without reparsing Output/a message
+	// string.
+	Err error
+}
+
+// StreamEventType identifies the kind of incremental event emitted during
+// a streaming Execute call.
+type StreamEventType string
+
+const (
+	StreamToken        StreamEventType = "token"
+	StreamToolCall     StreamEventType = "tool_call"
+	StreamToolProgress StreamEventType = "tool_progress"
+	StreamToolResult   StreamEventType = "tool_result"
+	StreamTrace        StreamEventType = "trace"
+	StreamDone         StreamEventType = "done"
+)
+
+// StreamEvent is a single incremental event emitted while Execute runs in
+// streaming mode.
+type StreamEvent struct {
+	Type   StreamEventType
+	Token  string
+	Tool   string
+	Output string
+	Error  string
+	Result AgentResult
 }
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/metrics"
+)
+
+var (
+	tasksBucket          = []byte("tasks")
+	tasksByCreatedBucket = []byte("tasks_by_created")
+)
+
+// BoltStore persists tasks in a single-file BoltDB database. Tasks are
+// keyed by id in the tasks bucket; tasks_by_created indexes the same rows
+// under sortKey so List can do a cheap descending Cursor scan without a
+// full-bucket load, the same ordering TaskStore and SQLiteStore use.
+type BoltStore struct {
+	watchHub
+
+	db *bolt.DB
+
+	// Metrics records task creation/completion. Defaults to a no-op
+	// recorder.
+	Metrics metrics.Recorder
+
+	// Logger records task lifecycle events keyed by task_id. Defaults to a
+	// no-op logger.
+	Logger hclog.Logger
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB database at path and
+// ensures its buckets exist.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt store: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(tasksBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(tasksByCreatedBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init bolt store: %w", err)
+	}
+	return &BoltStore{db: db, Metrics: metrics.NewNop(), Logger: hclog.NewNullLogger()}, nil
+}
+
+func (s *BoltStore) put(tx *bolt.Tx, t Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(tasksBucket).Put([]byte(t.ID), data); err != nil {
+		return err
+	}
+	return tx.Bucket(tasksByCreatedBucket).Put([]byte(sortKey(t)), []byte(t.ID))
+}
+
+// Create adds a new task.
+func (s *BoltStore) Create(instruction string) Task {
+	t := Task{
+		ID:          newTaskID(),
+		Instruction: instruction,
+		Status:      TaskPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+	_ = s.db.Update(func(tx *bolt.Tx) error {
+		return s.put(tx, t)
+	})
+	s.Metrics.ObserveTaskCreated()
+	s.publish(Event{Type: EventCreated, Task: t})
+	return t
+}
+
+// Update modifies a task.
+func (s *BoltStore) Update(id string, status TaskStatus, output, errMsg string) (Task, error) {
+	t, ok := s.Get(id)
+	if !ok {
+		return Task{}, fmt.Errorf("task not found")
+	}
+	if status != "" {
+		t.Status = status
+	}
+	if output != "" {
+		t.Output = output
+	}
+	if errMsg != "" {
+		t.Error = errMsg
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		return s.put(tx, t)
+	}); err != nil {
+		return Task{}, err
+	}
+	if status != "" {
+		s.Metrics.ObserveTaskStatus(string(status))
+	}
+	s.publish(Event{Type: EventUpdated, Task: t})
+	return t, nil
+}
+
+// Checkpoint records how far the agent loop has gotten on a task.
+func (s *BoltStore) Checkpoint(id string, iteration int, messages json.RawMessage) error {
+	t, ok := s.Get(id)
+	if !ok {
+		return fmt.Errorf("task not found")
+	}
+	t.Iteration = iteration
+	t.Messages = messages
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		return s.put(tx, t)
+	}); err != nil {
+		return err
+	}
+	s.publish(Event{Type: EventCheckpoint, Task: t})
+	return nil
+}
+
+// Get retrieves a task by ID.
+func (s *BoltStore) Get(id string) (Task, bool) {
+	var t Task
+	var ok bool
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(tasksBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil
+		}
+		ok = true
+		return nil
+	})
+	return t, ok
+}
+
+// List returns tasks matching filter, most recently created first,
+// resuming after cursor, via a descending scan of tasks_by_created.
+// cursor is the sort key of the last task returned on the previous page
+// (the same convention TaskStore and SQLiteStore use): Seek finds that
+// exact key and steps one position further back so the scan resumes
+// strictly before it, rather than re-including it.
+func (s *BoltStore) List(filter Filter, limit int, cursor string) ([]Task, string, error) {
+	var out []Task
+	var next string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		index := tx.Bucket(tasksByCreatedBucket)
+		tasks := tx.Bucket(tasksBucket)
+		c := index.Cursor()
+
+		var k, v []byte
+		if cursor == "" {
+			k, v = c.Last()
+		} else {
+			k, v = c.Seek([]byte(cursor))
+			if k != nil && string(k) >= cursor {
+				k, v = c.Prev()
+			}
+		}
+
+		for k != nil {
+			data := tasks.Get(v)
+			if data == nil {
+				k, v = c.Prev()
+				continue
+			}
+			var t Task
+			if err := json.Unmarshal(data, &t); err == nil && filter.matches(t) {
+				if limit > 0 && len(out) == limit {
+					next = sortKey(out[len(out)-1])
+					break
+				}
+				out = append(out, t)
+			}
+			k, v = c.Prev()
+		}
+		return nil
+	})
+	return out, next, err
+}
+
+// Close closes the underlying database handle.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,217 @@
+package task
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/metrics"
+)
+
+// SQLiteStore persists tasks (and their checkpointed message history) to a
+// SQLite database instead of the in-memory map TaskStore uses, so tasks
+// survive a process restart and a killed agent can resume a pending task
+// via Checkpoint/Get. It exposes the same method set as TaskStore.
+type SQLiteStore struct {
+	watchHub
+
+	db *sql.DB
+
+	// Metrics records task creation/completion. Defaults to a no-op
+	// recorder.
+	Metrics metrics.Recorder
+
+	// Logger records task lifecycle events keyed by task_id. Defaults to a
+	// no-op logger.
+	Logger hclog.Logger
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at dsn and
+// ensures its schema exists.
+func NewSQLiteStore(dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+	s := &SQLiteStore{db: db, Metrics: metrics.NewNop(), Logger: hclog.NewNullLogger()}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS tasks (
+			id          TEXT PRIMARY KEY,
+			instruction TEXT NOT NULL,
+			status      TEXT NOT NULL,
+			output      TEXT,
+			error       TEXT,
+			created_at  TEXT NOT NULL,
+			iteration   INTEGER NOT NULL DEFAULT 0,
+			messages    TEXT
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_status_created ON tasks(status, created_at DESC)`)
+	return err
+}
+
+// Create adds a new task.
+func (s *SQLiteStore) Create(instruction string) Task {
+	t := Task{
+		ID:          newTaskID(),
+		Instruction: instruction,
+		Status:      TaskPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+	_, _ = s.db.Exec(
+		`INSERT INTO tasks (id, instruction, status, output, error, created_at, iteration, messages) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, t.Instruction, string(t.Status), t.Output, t.Error, t.CreatedAt.Format(time.RFC3339Nano), t.Iteration, string(t.Messages),
+	)
+	s.Metrics.ObserveTaskCreated()
+	s.publish(Event{Type: EventCreated, Task: t})
+	return t
+}
+
+// Update modifies a task.
+func (s *SQLiteStore) Update(id string, status TaskStatus, output string, errMsg string) (Task, error) {
+	t, ok := s.Get(id)
+	if !ok {
+		return Task{}, fmt.Errorf("task not found")
+	}
+	if status != "" {
+		t.Status = status
+	}
+	if output != "" {
+		t.Output = output
+	}
+	if errMsg != "" {
+		t.Error = errMsg
+	}
+	_, err := s.db.Exec(
+		`UPDATE tasks SET status = ?, output = ?, error = ? WHERE id = ?`,
+		string(t.Status), t.Output, t.Error, t.ID,
+	)
+	if err != nil {
+		return Task{}, err
+	}
+	if status != "" {
+		s.Metrics.ObserveTaskStatus(string(status))
+	}
+	s.publish(Event{Type: EventUpdated, Task: t})
+	return t, nil
+}
+
+// Checkpoint records how far the agent loop has gotten on a task.
+func (s *SQLiteStore) Checkpoint(id string, iteration int, messages json.RawMessage) error {
+	res, err := s.db.Exec(
+		`UPDATE tasks SET iteration = ?, messages = ? WHERE id = ?`,
+		iteration, string(messages), id,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("task not found")
+	}
+	if t, ok := s.Get(id); ok {
+		s.publish(Event{Type: EventCheckpoint, Task: t})
+	}
+	return nil
+}
+
+// Get retrieves a task by ID.
+func (s *SQLiteStore) Get(id string) (Task, bool) {
+	row := s.db.QueryRow(
+		`SELECT id, instruction, status, output, error, created_at, iteration, messages FROM tasks WHERE id = ?`,
+		id,
+	)
+	t, err := scanTask(row)
+	if err != nil {
+		return Task{}, false
+	}
+	return t, true
+}
+
+// List returns tasks matching filter, most recently created first,
+// resuming after cursor. It relies on the idx_tasks_status_created index
+// for the status-filtered, descending-by-time scan.
+func (s *SQLiteStore) List(filter Filter, limit int, cursor string) ([]Task, string, error) {
+	query := `SELECT id, instruction, status, output, error, created_at, iteration, messages FROM tasks WHERE 1 = 1`
+	args := []any{}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, string(filter.Status))
+	}
+	if cursor != "" {
+		query += ` AND (created_at || '|' || id) < ?`
+		args = append(args, cursor)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit+1)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var out []Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			continue
+		}
+		out = append(out, t)
+	}
+
+	var next string
+	if limit > 0 && len(out) > limit {
+		next = sortKey(out[limit-1])
+		out = out[:limit]
+	}
+	return out, next, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (Task, error) {
+	var (
+		t         Task
+		status    string
+		createdAt string
+		messages  sql.NullString
+	)
+	if err := row.Scan(&t.ID, &t.Instruction, &status, &t.Output, &t.Error, &createdAt, &t.Iteration, &messages); err != nil {
+		return Task{}, err
+	}
+	t.Status = TaskStatus(status)
+	if parsed, err := time.Parse(time.RFC3339Nano, createdAt); err == nil {
+		t.CreatedAt = parsed
+	}
+	if messages.Valid && messages.String != "" {
+		t.Messages = json.RawMessage(messages.String)
+	}
+	return t, nil
+}
+
+// Close closes the underlying database handle.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -7,14 +7,28 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/metrics"
 )
 
 // TaskStore manages tasks in memory with optional persistence.
 type TaskStore struct {
+	watchHub
+
 	mu      sync.RWMutex
 	tasks   map[string]Task
 	persist bool
 	path    string
+
+	// Metrics records task creation/completion. Defaults to a no-op
+	// recorder.
+	Metrics metrics.Recorder
+
+	// Logger records task lifecycle events keyed by task_id. Defaults to a
+	// no-op logger.
+	Logger hclog.Logger
 }
 
 // NewTaskStore creates a TaskStore.
@@ -22,7 +36,7 @@ func NewTaskStore(persist bool, path string) *TaskStore {
 	if path == "" {
 		path = "tasks.json"
 	}
-	s := &TaskStore{tasks: map[string]Task{}, persist: persist, path: path}
+	s := &TaskStore{tasks: map[string]Task{}, persist: persist, path: path, Metrics: metrics.NewNop(), Logger: hclog.NewNullLogger()}
 	if persist {
 		_ = s.load()
 	}
@@ -41,6 +55,9 @@ func (s *TaskStore) Create(instruction string) Task {
 	}
 	s.tasks[task.ID] = task
 	s.saveIfPersist()
+	s.Metrics.ObserveTaskCreated()
+	s.Logger.Debug("task created", "task_id", task.ID)
+	s.publish(Event{Type: EventCreated, Task: task})
 	return task
 }
 
@@ -63,9 +80,32 @@ func (s *TaskStore) Update(id string, status TaskStatus, output string, errMsg s
 	}
 	s.tasks[id] = task
 	s.saveIfPersist()
+	if status != "" {
+		s.Metrics.ObserveTaskStatus(string(status))
+		s.Logger.Debug("task status updated", "task_id", id, "status", string(status))
+	}
+	s.publish(Event{Type: EventUpdated, Task: task})
 	return task, nil
 }
 
+// Checkpoint records how far the agent loop has gotten on a task: the
+// iteration it just finished and the message history needed to continue,
+// so a crashed or restarted process can resume from here via Resume.
+func (s *TaskStore) Checkpoint(id string, iteration int, messages json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return fmt.Errorf("task not found")
+	}
+	t.Iteration = iteration
+	t.Messages = messages
+	s.tasks[id] = t
+	s.saveIfPersist()
+	s.publish(Event{Type: EventCheckpoint, Task: t})
+	return nil
+}
+
 // Get retrieves a task by ID.
 func (s *TaskStore) Get(id string) (Task, bool) {
 	s.mu.RLock()
@@ -74,21 +114,33 @@ func (s *TaskStore) Get(id string) (Task, bool) {
 	return task, ok
 }
 
-// List returns recent tasks.
-func (s *TaskStore) List(limit int) []Task {
+// List returns tasks matching filter, most recently created first,
+// resuming after cursor.
+func (s *TaskStore) List(filter Filter, limit int, cursor string) ([]Task, string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	out := make([]Task, 0, len(s.tasks))
 	for _, t := range s.tasks {
-		out = append(out, t)
+		if filter.matches(t) {
+			out = append(out, t)
+		}
 	}
 	sort.Slice(out, func(i, j int) bool {
-		return out[i].CreatedAt.After(out[j].CreatedAt)
+		return sortKey(out[i]) > sortKey(out[j])
 	})
+	if cursor != "" {
+		idx := 0
+		for idx < len(out) && sortKey(out[idx]) >= cursor {
+			idx++
+		}
+		out = out[idx:]
+	}
+	var next string
 	if limit > 0 && len(out) > limit {
+		next = sortKey(out[limit-1])
 		out = out[:limit]
 	}
-	return out
+	return out, next, nil
 }
 
 func (s *TaskStore) load() error {
@@ -0,0 +1,98 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Filter narrows List results. A zero Filter matches every task.
+type Filter struct {
+	Status TaskStatus
+}
+
+func (f Filter) matches(t Task) bool {
+	return f.Status == "" || t.Status == f.Status
+}
+
+// EventType identifies what changed about a task in an Event.
+type EventType string
+
+const (
+	EventCreated    EventType = "created"
+	EventUpdated    EventType = "updated"
+	EventCheckpoint EventType = "checkpoint"
+)
+
+// Event is published on a Store's Watch channel whenever a task changes.
+type Event struct {
+	Type EventType
+	Task Task
+}
+
+// Store is the persistence interface the agent loop and API depend on.
+// TaskStore (in-memory), SQLiteStore, and BoltStore all satisfy it.
+type Store interface {
+	Create(instruction string) Task
+	Update(id string, status TaskStatus, output, errMsg string) (Task, error)
+	Checkpoint(id string, iteration int, messages json.RawMessage) error
+	Get(id string) (Task, bool)
+	// List returns up to limit tasks matching filter, most recently
+	// created first, resuming after cursor (an opaque value returned by a
+	// prior call; empty starts at the newest task). The returned cursor
+	// is empty once there are no more results.
+	List(filter Filter, limit int, cursor string) (tasks []Task, nextCursor string, err error)
+	// Watch streams task lifecycle events until ctx is done, at which
+	// point the returned channel is closed.
+	Watch(ctx context.Context) <-chan Event
+}
+
+// sortKey orders tasks most-recently-created-first with a stable
+// tiebreak, and doubles as the opaque cursor format for List: every Store
+// implementation (in-memory, SQLite, Bolt) keys its descending scan off
+// this same "<created_at RFC3339Nano>|<id>" string.
+func sortKey(t Task) string {
+	return t.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + t.ID
+}
+
+// watchHub fans task events out to Watch subscribers. Store
+// implementations embed it and call publish after each mutation.
+type watchHub struct {
+	mu       sync.Mutex
+	watchers map[chan Event]struct{}
+}
+
+// Watch registers a new subscriber, deregistering and closing its channel
+// once ctx is done.
+func (h *watchHub) Watch(ctx context.Context) <-chan Event {
+	ch := make(chan Event, 8)
+	h.mu.Lock()
+	if h.watchers == nil {
+		h.watchers = map[chan Event]struct{}{}
+	}
+	h.watchers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.watchers, ch)
+		h.mu.Unlock()
+		close(ch)
+	}()
+	return ch
+}
+
+// publish fans ev out to every active subscriber, dropping it for any
+// subscriber whose buffer is full rather than blocking the caller.
+func (h *watchHub) publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.watchers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
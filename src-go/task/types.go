@@ -1,6 +1,9 @@
 package task
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // TaskStatus represents the state of a task.
 type TaskStatus string
@@ -20,4 +23,11 @@ type Task struct {
 	Output      string     `json:"output,omitempty"`
 	Error       string     `json:"error,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+
+	// Iteration and Messages checkpoint the agent loop's progress so a
+	// pending or running task can be resumed after a crash or restart.
+	// Messages holds a JSON-encoded []llm.Message; task intentionally
+	// doesn't import llm to avoid coupling the store to the LLM layer.
+	Iteration int             `json:"iteration,omitempty"`
+	Messages  json.RawMessage `json:"messages,omitempty"`
 }
@@ -1,23 +1,48 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/tunapro1234/base-agent/src-go/metrics"
 )
 
 // ToolRegistry manages tool registration and execution.
 type ToolRegistry struct {
 	mu    sync.RWMutex
 	tools map[string]ToolEntry
+
+	// Metrics records execution count/latency for every Execute call.
+	// Defaults to a no-op recorder.
+	Metrics metrics.Recorder
+
+	// Logger records per-execution tool/duration_ms observations. Defaults
+	// to a no-op logger.
+	Logger hclog.Logger
 }
 
 // NewToolRegistry creates a new ToolRegistry.
 func NewToolRegistry() *ToolRegistry {
-	return &ToolRegistry{tools: map[string]ToolEntry{}}
+	return &ToolRegistry{tools: map[string]ToolEntry{}, Metrics: metrics.NewNop(), Logger: hclog.NewNullLogger()}
 }
 
-// Register adds a new tool to the registry.
+// Register adds a new tool to the registry using the legacy
+// args-in/string-out handler signature, adapted into ToolHandlerV2 so it
+// still benefits from Execute's timeout and cancellation handling.
 func (r *ToolRegistry) Register(name string, handler ToolHandler, schema ToolSchema) error {
+	if name == "" || handler == nil {
+		return fmt.Errorf("invalid tool")
+	}
+	return r.RegisterV2(name, adaptLegacyHandler(handler), schema)
+}
+
+// RegisterV2 adds a new tool to the registry using the context-aware
+// handler signature.
+func (r *ToolRegistry) RegisterV2(name string, handler ToolHandlerV2, schema ToolSchema) error {
 	if name == "" || handler == nil {
 		return fmt.Errorf("invalid tool")
 	}
@@ -37,19 +62,71 @@ func (r *ToolRegistry) Register(name string, handler ToolHandler, schema ToolSch
 	return nil
 }
 
-// Execute runs a tool by name with args.
-func (r *ToolRegistry) Execute(name string, args map[string]any) ToolResult {
+// adaptLegacyHandler wraps a ToolHandler so it satisfies ToolHandlerV2,
+// ignoring ctx and emit since the legacy signature has no way to use
+// either.
+func adaptLegacyHandler(handler ToolHandler) ToolHandlerV2 {
+	return func(ctx context.Context, args map[string]any, emit func(ToolProgress)) (ToolResult, error) {
+		out, err := handler(args)
+		if err != nil {
+			return ToolResult{}, err
+		}
+		return ToolResult{Success: true, Output: out}, nil
+	}
+}
+
+// Execute runs a tool by name with args, bounding it by the caller's ctx
+// and the tool's own schema Timeout (whichever elapses first), and
+// reporting incremental progress through emit as the handler reports it.
+// emit may be nil if the caller doesn't care about progress. If ctx is
+// canceled or the timeout elapses before the handler returns, Execute
+// gives up and reports it as a failed result rather than blocking for the
+// handler, which may still be running in the background.
+func (r *ToolRegistry) Execute(ctx context.Context, name string, args map[string]any, emit func(ToolProgress)) ToolResult {
+	start := time.Now()
 	r.mu.RLock()
 	entry, ok := r.tools[name]
 	r.mu.RUnlock()
 	if !ok {
+		r.Metrics.ObserveToolExecution(name, false, time.Since(start))
 		return ToolResult{Success: false, Error: "tool not found"}
 	}
-	out, err := entry.Handler(args)
-	if err != nil {
-		return ToolResult{Success: false, Error: err.Error()}
+	if emit == nil {
+		emit = func(ToolProgress) {}
+	}
+
+	runCtx := ctx
+	if entry.Schema.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, entry.Schema.Timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result ToolResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := entry.Handler(runCtx, args, emit)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil {
+			r.Metrics.ObserveToolExecution(name, false, time.Since(start))
+			r.Logger.Error("tool execution failed", "tool", name, "error", o.err.Error(), "duration_ms", time.Since(start).Milliseconds())
+			return ToolResult{Success: false, Error: o.err.Error()}
+		}
+		r.Metrics.ObserveToolExecution(name, o.result.Success, time.Since(start))
+		r.Logger.Debug("tool executed", "tool", name, "success", o.result.Success, "duration_ms", time.Since(start).Milliseconds())
+		return o.result
+	case <-runCtx.Done():
+		r.Metrics.ObserveToolExecution(name, false, time.Since(start))
+		r.Logger.Error("tool execution canceled", "tool", name, "duration_ms", time.Since(start).Milliseconds())
+		return ToolResult{Success: false, Error: "canceled"}
 	}
-	return ToolResult{Success: true, Output: out}
 }
 
 // GetSchemas returns the registered tool schemas.
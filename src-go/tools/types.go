@@ -1,10 +1,23 @@
 package tools
 
+import (
+	"context"
+	"time"
+)
+
 // ToolSchema defines the shape of tool metadata exposed to LLMs.
 type ToolSchema struct {
 	Name        string
 	Description string
 	Parameters  map[string]any
+	// Timeout bounds how long Execute lets this tool run before canceling
+	// its context. Zero means no tool-specific timeout (the caller's ctx,
+	// e.g. an HTTP request deadline, still applies).
+	Timeout time.Duration
+	// Cancellable marks a tool as safe to abort mid-run via ctx
+	// cancellation. Execute always propagates cancellation regardless;
+	// this only documents intent for callers deciding whether to cancel.
+	Cancellable bool
 }
 
 // ToolResult captures the outcome of a tool execution.
@@ -14,12 +27,27 @@ type ToolResult struct {
 	Error   string
 }
 
+// ToolProgress is an incremental status update a running tool can emit
+// before returning its final ToolResult.
+type ToolProgress struct {
+	Message string
+}
+
 // ToolHandler executes a tool with the given arguments.
+//
+// Deprecated: use ToolHandlerV2, which observes ctx cancellation/timeout
+// and can emit progress. Handlers registered via Register are adapted
+// into ToolHandlerV2 automatically.
 type ToolHandler func(args map[string]any) (string, error)
 
+// ToolHandlerV2 executes a tool with the given arguments, observing ctx
+// cancellation/timeout and optionally reporting progress through emit
+// before returning its final result.
+type ToolHandlerV2 func(ctx context.Context, args map[string]any, emit func(ToolProgress)) (ToolResult, error)
+
 // ToolEntry stores tool metadata and handler.
 type ToolEntry struct {
 	Name    string
-	Handler ToolHandler
+	Handler ToolHandlerV2
 	Schema  ToolSchema
 }